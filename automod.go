@@ -0,0 +1,124 @@
+package tmi
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// AutoModCategory for the kind of content AutoMod flagged.
+type AutoModCategory int
+
+const (
+	// AutoModUnknown for a category code that is not recognized
+	AutoModUnknown AutoModCategory = iota - 1
+	// AutoModAggression for hostility, code A
+	AutoModAggression
+	// AutoModProfanity for swearing, code P
+	AutoModProfanity
+	// AutoModSexual for sexual content, code S
+	AutoModSexual
+	// AutoModIdentity for discrimination based on identity, code I
+	AutoModIdentity
+)
+
+func (ac AutoModCategory) String() string {
+	switch ac {
+	case AutoModAggression:
+		return "aggression"
+	case AutoModProfanity:
+		return "profanity"
+	case AutoModSexual:
+		return "sexual"
+	case AutoModIdentity:
+		return "identity"
+	default:
+		return "unknown"
+	}
+}
+
+// AutoModFlag is a part of a message that AutoMod classified, from the flags tag.
+type AutoModFlag struct {
+	Categories []AutoModLevel `json:"categories"` // may be empty when Twitch flags text without a category
+	Position   EmotePosition  `json:"position"`   // where the flagged text is, counted in UTF-16 code units like emote positions
+	Text       string         `json:"text"`       // the flagged text
+}
+
+// AutoModLevel is a category AutoMod flagged text for and how severe it is.
+type AutoModLevel struct {
+	Category AutoModCategory `json:"category"`
+	Code     string          `json:"code"`  // the category as sent, e.g. A
+	Level    int             `json:"level"` // severity, higher is more severe
+}
+
+// Level returns the flag's severity for category, 0 if the flag does not have category.
+func (f AutoModFlag) Level(category AutoModCategory) int {
+	var level int
+	for _, c := range f.Categories {
+		if c.Category == category && c.Level > level {
+			level = c.Level
+		}
+	}
+	return level
+}
+
+// parseAutoModFlags parses a flags tag like 0-4:A.3/P.6,10-15:P.6 against the message text.
+func parseAutoModFlags(flags string, text string) []AutoModFlag {
+	var parsed []AutoModFlag
+	if flags == "" {
+		return parsed
+	}
+
+	var u16 = utf16.Encode([]rune(text))
+	for _, flag := range strings.Split(flags, ",") {
+		var colon = strings.Index(flag, ":")
+		if colon < 0 {
+			continue
+		}
+		var bounds = strings.SplitN(flag[:colon], "-", 2)
+		if len(bounds) != 2 {
+			continue
+		}
+		var start, errStart = strconv.Atoi(bounds[0])
+		var end, errEnd = strconv.Atoi(bounds[1])
+		if errStart != nil || errEnd != nil || start < 0 || start > end || end >= len(u16) {
+			continue
+		}
+
+		var autoModFlag = AutoModFlag{
+			Categories: []AutoModLevel{},
+			Position:   EmotePosition{start, end},
+			Text:       string(utf16.Decode(u16[start : end+1])),
+		}
+		for _, category := range strings.Split(flag[colon+1:], "/") {
+			var parts = strings.SplitN(category, ".", 2)
+			if parts[0] == "" {
+				continue
+			}
+			var level AutoModLevel
+			level.Code = parts[0]
+			level.Category = parseAutoModCategory(parts[0])
+			if len(parts) == 2 {
+				level.Level, _ = strconv.Atoi(parts[1])
+			}
+			autoModFlag.Categories = append(autoModFlag.Categories, level)
+		}
+		parsed = append(parsed, autoModFlag)
+	}
+	return parsed
+}
+
+func parseAutoModCategory(code string) AutoModCategory {
+	switch code {
+	case "A":
+		return AutoModAggression
+	case "P":
+		return AutoModProfanity
+	case "S":
+		return AutoModSexual
+	case "I":
+		return AutoModIdentity
+	default:
+		return AutoModUnknown
+	}
+}
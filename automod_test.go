@@ -0,0 +1,60 @@
+package tmi
+
+import (
+	"testing"
+)
+
+func TestParseAutoModFlags(t *testing.T) {
+	tests := []struct {
+		flags string
+		text  string
+		want  []AutoModFlag
+	}{
+		{"", "hello", []AutoModFlag{}},
+		{"0-4:A.3/P.6,10-13:S.7", "idiot and damn", []AutoModFlag{
+			{[]AutoModLevel{{AutoModAggression, "A", 3}, {AutoModProfanity, "P", 6}}, EmotePosition{0, 4}, "idiot"},
+			{[]AutoModLevel{{AutoModSexual, "S", 7}}, EmotePosition{10, 13}, "damn"},
+		}},
+		// 😀 is two UTF-16 code units
+		{"3-6:I.5", "😀 darn", []AutoModFlag{
+			{[]AutoModLevel{{AutoModIdentity, "I", 5}}, EmotePosition{3, 6}, "darn"},
+		}},
+		{"0-3:,5-6:X.2", "word up", []AutoModFlag{
+			{[]AutoModLevel{}, EmotePosition{0, 3}, "word"},
+			{[]AutoModLevel{{AutoModUnknown, "X", 2}}, EmotePosition{5, 6}, "up"},
+		}},
+		{"0-40:A.3,bad", "short", []AutoModFlag{}},
+	}
+
+	for _, test := range tests {
+		got := parseAutoModFlags(test.flags, test.text)
+		if len(got) != len(test.want) {
+			t.Errorf("%v: got %+v, want %+v", test.flags, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i].Position != test.want[i].Position || got[i].Text != test.want[i].Text {
+				t.Errorf("%v: Flags[%v] got %+v, want %+v", test.flags, i, got[i], test.want[i])
+			}
+			if len(got[i].Categories) != len(test.want[i].Categories) {
+				t.Errorf("%v: Flags[%v].Categories got %+v, want %+v", test.flags, i, got[i].Categories, test.want[i].Categories)
+				continue
+			}
+			for j := range got[i].Categories {
+				if got[i].Categories[j] != test.want[i].Categories[j] {
+					t.Errorf("%v: Flags[%v].Categories[%v] got %+v, want %+v", test.flags, i, j, got[i].Categories[j], test.want[i].Categories[j])
+				}
+			}
+		}
+	}
+}
+
+func TestAutoModFlagLevel(t *testing.T) {
+	ircData, _ := parseIRCMessage("@flags=0-4:A.3/P.6 :u!u@u.tmi.twitch.tv PRIVMSG #c :idiot")
+	msg := parsePrivateMessage(ircData, nil)
+	if len(msg.Flags) != 1 {
+		t.Fatalf("Flags: got %+v, want 1 flag", msg.Flags)
+	}
+	assertIntsEqual(t, "Level(AutoModProfanity)", msg.Flags[0].Level(AutoModProfanity), 6)
+	assertIntsEqual(t, "Level(AutoModSexual)", msg.Flags[0].Level(AutoModSexual), 0)
+}
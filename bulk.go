@@ -0,0 +1,167 @@
+package tmi
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bulkAnswerTimeout is how long a bulk operation waits for the answer to each user's command.
+const bulkAnswerTimeout = time.Second * 10
+
+// BulkResult is how a bulk operation's command for one user turned out.
+type BulkResult struct {
+	User   string           `json:"user"`
+	Result ModerationResult `json:"result"`
+	Err    error            `json:"-"`     // the error of the command's Wait variant, e.g. a *NoticeError
+	Done   int              `json:"done"`  // number of users with a result so far, including this one
+	Total  int              `json:"total"` // number of users that are not blank, 0 for a stream
+}
+
+// BulkBan bans each of users in channel like BanWait, and returns a channel of each user's result.
+// Blank users are skipped and not counted in Total, so Done reaches Total with the last result. See BulkBanStream.
+func (c *Client) BulkBan(ctx context.Context, channel string, users []string, reason string) (<-chan BulkResult, error) {
+	var list = userList(users)
+	return c.bulkBan(ctx, channel, list, len(list), reason)
+}
+
+// BulkBanStream bans each user received from users in channel like BanWait, until users is closed or ctx is done.
+// Commands are paced under the client's moderator rate limit, shared with its other moderation commands and bulk operations,
+// which is RLimMsgMod for IRC and RLimHelixDefault after UseHelix. The answer to each is waited for up to 10 seconds.
+// Results arrive in the order they are answered, and the returned channel is closed once every sent command has a result.
+// It must be read until it is closed.
+func (c *Client) BulkBanStream(ctx context.Context, channel string, users <-chan string, reason string) (<-chan BulkResult, error) {
+	return c.bulkBan(ctx, channel, users, 0, reason)
+}
+
+// BulkTimeout times out each of users in channel for d like TimeoutWait, and returns a channel of each user's result.
+// d must be whole seconds between 1 second and 2 weeks. See BulkBanStream.
+func (c *Client) BulkTimeout(ctx context.Context, channel string, users []string, d time.Duration) (<-chan BulkResult, error) {
+	var list = userList(users)
+	return c.bulkTimeout(ctx, channel, list, len(list), d)
+}
+
+// BulkTimeoutStream times out each user received from users in channel for d like TimeoutWait,
+// until users is closed or ctx is done. d must be whole seconds between 1 second and 2 weeks. See BulkBanStream.
+func (c *Client) BulkTimeoutStream(ctx context.Context, channel string, users <-chan string, d time.Duration) (<-chan BulkResult, error) {
+	return c.bulkTimeout(ctx, channel, users, 0, d)
+}
+
+// BulkUnban unbans each of users in channel like UnbanWait, and returns a channel of each user's result.
+// See BulkBanStream.
+func (c *Client) BulkUnban(ctx context.Context, channel string, users []string) (<-chan BulkResult, error) {
+	var list = userList(users)
+	return c.bulkUnban(ctx, channel, list, len(list))
+}
+
+// BulkUnbanStream unbans each user received from users in channel like UnbanWait, until users is closed or ctx is done.
+// See BulkBanStream.
+func (c *Client) BulkUnbanStream(ctx context.Context, channel string, users <-chan string) (<-chan BulkResult, error) {
+	return c.bulkUnban(ctx, channel, users, 0)
+}
+
+func (c *Client) bulkBan(ctx context.Context, channel string, users <-chan string, total int, reason string) (<-chan BulkResult, error) {
+	if err := c.checkModerator(channel); err != nil {
+		return nil, err
+	}
+	return c.bulk(ctx, channel, users, total, banAnswers, func(user string) error {
+		if len(reason)+len(user) > 490 {
+			return errors.New("user + reason must be shorter than 490 characters")
+		}
+		return c.moderator.Ban(channel, user, reason)
+	}), nil
+}
+
+func (c *Client) bulkTimeout(ctx context.Context, channel string, users <-chan string, total int, d time.Duration) (<-chan BulkResult, error) {
+	if d < minTimeoutDuration || d > maxTimeoutDuration {
+		return nil, errors.New("timeout duration must be between 1 second and 2 weeks")
+	}
+	if d%time.Second != 0 {
+		return nil, errors.New("timeout duration must be whole seconds")
+	}
+	if err := c.checkModerator(channel); err != nil {
+		return nil, err
+	}
+	var seconds = strconv.Itoa(int(d / time.Second))
+	return c.bulk(ctx, channel, users, total, timeoutAnswers, func(user string) error {
+		return c.moderator.Timeout(channel, user, seconds)
+	}), nil
+}
+
+func (c *Client) bulkUnban(ctx context.Context, channel string, users <-chan string, total int) (<-chan BulkResult, error) {
+	if err := c.checkModerator(channel); err != nil {
+		return nil, err
+	}
+	return c.bulk(ctx, channel, users, total, unbanAnswers, func(user string) error {
+		return c.moderator.Unban(channel, user)
+	}), nil
+}
+
+// bulk sends a command with send for each user received from users in channel, paced under the moderator rate limit,
+// waiting for the answers concurrently so a slow answer does not hold up the next user.
+func (c *Client) bulk(ctx context.Context, channel string, users <-chan string, total int, answers []NoticeMsgID, send func(user string) error) <-chan BulkResult {
+	var results = make(chan BulkResult)
+	var limiter = c.rLimiterMod
+
+	go func() {
+		var wg sync.WaitGroup
+		var mutex sync.Mutex // keeps Done in order
+		var done int
+		defer func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for {
+			var user string
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case user, ok = <-users:
+				if !ok {
+					return
+				}
+			}
+			if user = strings.TrimSpace(user); user == "" {
+				continue
+			}
+			if !limiter.waitContext(ctx) {
+				return
+			}
+
+			wg.Add(1)
+			go func(user string) {
+				defer wg.Done()
+				var answerCtx, cancel = context.WithTimeout(ctx, bulkAnswerTimeout)
+				defer cancel()
+				var result = newModerationResult(channel, user)
+				var err = c.checkModerator(result.Channel)
+				if err == nil {
+					result, err = c.awaitModeration(answerCtx, result, answers, func() error { return send(user) })
+				}
+
+				mutex.Lock()
+				done++
+				results <- BulkResult{User: user, Result: result, Err: err, Done: done, Total: total}
+				mutex.Unlock()
+			}(user)
+		}
+	}()
+	return results
+}
+
+// userList returns a closed channel holding the users that are not blank, so its length is the bulk operation's Total.
+func userList(users []string) chan string {
+	var ch = make(chan string, len(users))
+	for _, user := range users {
+		if user = strings.TrimSpace(user); user != "" {
+			ch <- user
+		}
+	}
+	close(ch)
+	return ch
+}
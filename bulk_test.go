@@ -0,0 +1,118 @@
+package tmi
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// answerBans answers each /ban and /unban sent by c like the server would, until ctx is done.
+func answerBans(ctx context.Context, c *Client) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line := <-c.outbound:
+			var fields = strings.Fields(line)
+			var command, user = fields[2], fields[3]
+			switch {
+			case command == ":/ban" && user == "banned":
+				c.handleIRCMessage("@msg-id=already_banned :tmi.twitch.tv NOTICE #c :" + user + " is already banned in this channel.")
+			case command == ":/ban":
+				c.handleIRCMessage("@msg-id=ban_success :tmi.twitch.tv NOTICE #c :" + user + " is now banned from this channel.")
+			case command == ":/unban":
+				c.handleIRCMessage("@msg-id=unban_success :tmi.twitch.tv NOTICE #c :" + user + " is no longer banned from this channel.")
+			}
+		}
+	}
+}
+
+func TestBulkBan(t *testing.T) {
+	c := NewClient(NewClientConfig("me", "oauth:token"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go answerBans(ctx, c)
+
+	results, err := c.BulkBan(ctx, "c", []string{"a", "banned", "", "b"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var users, failed []string
+	var done []int
+	for r := range results {
+		users = append(users, r.User)
+		done = append(done, r.Done)
+		assertIntsEqual(t, "Total", r.Total, 3)
+		if r.Err != nil {
+			failed = append(failed, r.User)
+			if !errors.Is(r.Err, ErrNoticeCommandError) {
+				t.Errorf("%v: got error %v, want a command error", r.User, r.Err)
+			}
+		}
+	}
+	sort.Strings(users)
+	assertStringSlicesEqual(t, "users", users, []string{"a", "b", "banned"})
+	assertStringSlicesEqual(t, "failed", failed, []string{"banned"})
+	assertIntsEqual(t, "done", len(done), 3)
+	for i := range done {
+		assertIntsEqual(t, "Done", done[i], i+1)
+	}
+
+	if _, err := c.BulkTimeout(ctx, "c", []string{"a"}, time.Hour*24*15); err == nil {
+		t.Errorf("expected an error for a timeout longer than 2 weeks")
+	}
+	if _, err := c.BulkTimeout(ctx, "c", []string{"a"}, time.Millisecond*1500); err == nil {
+		t.Errorf("expected an error for a timeout that isn't whole seconds")
+	}
+}
+
+func TestBulkSharedRateLimit(t *testing.T) {
+	c := NewClient(NewClientConfig("me", "oauth:token"))
+	c.SetModeratorRateLimit(RateLimit{Burst: 1, Rate: time.Hour})
+	c.Unban("c", "a")
+	<-c.outbound
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	results, err := c.BulkUnban(ctx, "c", []string{"b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for r := range results {
+		t.Errorf("got result %v, want none while Unban used the only token", r)
+	}
+	assertIntsEqual(t, "outbound", len(c.outbound), 0)
+}
+
+func TestBulkCancel(t *testing.T) {
+	c := NewClient(NewClientConfig("me", "oauth:token"))
+	answerCtx, stopAnswering := context.WithCancel(context.Background())
+	defer stopAnswering()
+	go answerBans(answerCtx, c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	users := make(chan string)
+	results, err := c.BulkUnbanStream(ctx, "c", users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users <- "a"
+	r := <-results
+	assertStringsEqual(t, "User", r.User, "a")
+	assertIntsEqual(t, "Total", r.Total, 0)
+	assertStringsEqual(t, "MsgID", string(r.Result.MsgID), string(MsgIDUnbanSuccess))
+
+	cancel()
+	select {
+	case r, ok := <-results:
+		if ok {
+			t.Errorf("got result %v after cancelling, want the channel closed", r)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("results was not closed after cancelling")
+	}
+}
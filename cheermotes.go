@@ -0,0 +1,73 @@
+package tmi
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Cheermote is a cheer in a bits message, like Cheer100.
+type Cheermote struct {
+	Prefix   string        `json:"prefix"`   // the cheermote name as typed, e.g. Cheer
+	Amount   int           `json:"amount"`   // number of bits cheered with this cheermote
+	Position EmotePosition `json:"position"` // where the cheermote is in the text, counted in UTF-16 code units like emote positions
+}
+
+// DefaultCheermotePrefixes are Twitch's global cheermotes, used to find cheermotes in bits messages
+// when ClientConfig.CheermotePrefixes is not set.
+var DefaultCheermotePrefixes = []string{
+	"Cheer", "DoodleCheer", "BibleThump", "cheerwhal", "Corgo", "Scoops", "uni", "ShowLove", "Party",
+	"SeemsGood", "Pride", "Kappa", "FrankerZ", "HeyGuys", "DansGame", "EleGiggle", "TriHard", "Kreygasm",
+	"4Head", "SwiftRage", "NotLikeThis", "FailFish", "VoHiYo", "PJSalt", "MrDestructoid", "bday",
+	"RIPCheer", "Shamrock", "BitBoss", "Streamlabs", "Muxy", "HolidayCheer", "Goal", "Anon", "Charity",
+}
+
+// ParseCheermotes finds each word of text that is one of prefixes (case insensitive) followed by a bits amount.
+func ParseCheermotes(text string, prefixes []string) []Cheermote {
+	var cheermotes []Cheermote
+	if len(prefixes) == 0 {
+		return cheermotes
+	}
+
+	var idx int
+	for _, word := range strings.Split(text, " ") {
+		var length = len(utf16.Encode([]rune(word)))
+		if cheermote, ok := parseCheermote(word, prefixes); ok {
+			cheermote.Position = EmotePosition{idx, idx + length - 1}
+			cheermotes = append(cheermotes, cheermote)
+		}
+		idx += length + 1
+	}
+	return cheermotes
+}
+
+// CheermoteBits returns the sum of the message's cheermote amounts, which should match Bits.
+func (m PrivateMessage) CheermoteBits() int {
+	var total int
+	for _, cheermote := range m.Cheermotes {
+		total += cheermote.Amount
+	}
+	return total
+}
+
+// parseCheermote parses word as one of prefixes (case insensitive) followed by a bits amount.
+func parseCheermote(word string, prefixes []string) (Cheermote, bool) {
+	var digits = len(word)
+	for digits > 0 && word[digits-1] >= '0' && word[digits-1] <= '9' {
+		digits--
+	}
+	if digits == 0 || digits == len(word) {
+		return Cheermote{}, false
+	}
+	var prefix = word[:digits]
+	for _, p := range prefixes {
+		if strings.EqualFold(p, prefix) {
+			var amount, err = strconv.Atoi(word[digits:])
+			if err != nil || amount <= 0 {
+				return Cheermote{}, false
+			}
+			return Cheermote{Prefix: prefix, Amount: amount}, true
+		}
+	}
+	return Cheermote{}, false
+}
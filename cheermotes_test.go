@@ -0,0 +1,62 @@
+package tmi
+
+import (
+	"testing"
+)
+
+func TestParseCheermotes(t *testing.T) {
+	tests := []struct {
+		text     string
+		prefixes []string
+		want     []Cheermote
+	}{
+		{"cheer100 great stream Kappa50", DefaultCheermotePrefixes, []Cheermote{
+			{"cheer", 100, EmotePosition{0, 7}},
+			{"Kappa", 50, EmotePosition{22, 28}},
+		}},
+		// 😀 is two UTF-16 code units
+		{"😀 Cheer1", DefaultCheermotePrefixes, []Cheermote{{"Cheer", 1, EmotePosition{3, 8}}}},
+		{"MyCheer25 Cheer10", []string{"mycheer"}, []Cheermote{{"MyCheer", 25, EmotePosition{0, 8}}}},
+		{"Cheer Cheer0 100 Cheer10x", DefaultCheermotePrefixes, nil},
+		{"Cheer100", nil, nil},
+	}
+
+	for _, test := range tests {
+		got := ParseCheermotes(test.text, test.prefixes)
+		if len(got) != len(test.want) {
+			t.Errorf("%v: got %+v, want %+v", test.text, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%v: Cheermotes[%v] got %+v, want %+v", test.text, i, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestCheermoteBits(t *testing.T) {
+	ircData, _ := parseIRCMessage("@bits=150 :u!u@u.tmi.twitch.tv PRIVMSG #c :cheer100 great stream Kappa50")
+	msg := parsePrivateMessage(ircData, nil)
+	assertIntsEqual(t, "CheermoteBits", msg.CheermoteBits(), msg.Bits)
+
+	ircData, _ = parseIRCMessage(":u!u@u.tmi.twitch.tv PRIVMSG #c :cheer100 without bits")
+	msg = parsePrivateMessage(ircData, nil)
+	assertIntsEqual(t, "Cheermotes", len(msg.Cheermotes), 0)
+}
+
+func TestConfigCheermotePrefixes(t *testing.T) {
+	config := NewClientConfig("", "")
+	config.CheermotePrefixes = []string{"MyCheer"}
+	c := NewClient(config)
+
+	var got PrivateMessage
+	c.OnPrivateMessage(func(m PrivateMessage) {
+		got = m
+	})
+	c.handleIRCMessage("@bits=35 :u!u@u.tmi.twitch.tv PRIVMSG #c :MyCheer25 Cheer10")
+
+	if len(got.Cheermotes) != 1 || got.Cheermotes[0].Prefix != "MyCheer" {
+		t.Errorf("Cheermotes: got %+v, want only MyCheer25", got.Cheermotes)
+	}
+}
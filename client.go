@@ -14,52 +14,93 @@ const PingSignature = "go-tmi-ws"
 
 // Client to configure callbacks and manage the connection.
 type Client struct {
-	channels         map[string]bool
+	capabilities     []string // capabilities the server granted on this connection.
+	capsMutex        sync.Mutex
+	cancelReader     context.CancelFunc // stops the reader of the current connection, so a handover can retire it.
+	channels         map[string]channelState
 	channelsMutex    sync.Mutex
 	config           ClientConfig
 	conn             *websocket.Conn
 	connected        atomicBool
 	done             func(error) // callback function for fatal errors.
+	echoLogin        string      // login IgnoreSelf compares against until the own user ID is known.
+	handingOver      atomicBool  // set while a handover is opening a new connection.
 	handlers         onMessageHandlers
-	inbound          chan string   // for sending inbound messages to the handlers, acts as a buffer.
+	helixChat        *HelixChat          // set by UseHelixChat.
+	handover         func()              // starts a make-before-break reconnect, set by connect().
+	history          *History            // set by KeepHistory.
+	inbound          chan string         // for sending inbound messages to the handlers, acts as a buffer.
+	me               *Self               // own global state, from GLOBALUSERSTATE.
+	meIn             map[string]Self     // own state in each joined channel, from USERSTATE.
+	moderator        Moderator           // sends moderation and chat settings commands, set by SetModerator.
+	moderations      []*moderationWaiter // moderation commands waiting for their NOTICE, oldest first.
+	moderationsMutex sync.Mutex
 	notifDisconnect  notifier      // used for disconnect call notifications
 	outbound         chan string   // for sending outbound messages to the writer.
+	presence         *Presence     // set by TrackPresence.
+	rcvdCapReply     bool          // set when the CAP ACK or NAK is handled, only used by the handler.
 	rcvdMsg          chan struct{} // when conn reads, notifies ping loop.
 	rcvdPong         chan struct{} // when pong received, notifies ping loop.
 	reconnectCounter int           // for keeping track of reconnect attempts before a successful attempt.
+	rcvdWelcome      bool          // set when 001 is handled, only used by the handler.
+	recentIDs        *recentIDs    // set by a handover to drop messages received on both connections, only used by the handler.
 	rLimiterJoins    *RateLimiter
+	rLimiterMsgs     *RateLimiter            // applied to PRIVMSG lines in the writer.
+	rLimiterMod      *RateLimiter            // charged by every moderation command, and paces bulk moderation.
+	rooms            map[string]RoomSettings // merged ROOMSTATE settings of joined channels.
+	roomsMutex       sync.Mutex
+	selfMutex        sync.Mutex
+	users            *UserDirectory // set by TrackUsers.
+	writeMutex       sync.Mutex     // guards conn, which a handover swaps, and writes to it.
 }
 
 type onMessageHandlers struct {
-	onUnsetMessage           func(UnsetMessage)
-	onConnected              func()
-	onClearChatMessage       func(ClearChatMessage)
-	onClearMsgMessage        func(ClearMsgMessage)
-	onGlobalUserstateMessage func(GlobalUserstateMessage)
-	onHostTargetMessage      func(HostTargetMessage)
-	onNoticeMessage          func(NoticeMessage)
-	onReconnectMessage       func(ReconnectMessage)
-	onRoomstateMessage       func(RoomstateMessage)
-	onUserNoticeMessage      func(UsernoticeMessage)
-	onUserstateMessage       func(UserstateMessage)
-	onNamesMessage           func(NamesMessage)
-	onJoinMessage            func(JoinMessage)
-	onPartMessage            func(PartMessage)
-	onPingMessage            func(PingMessage)
-	onPongMessage            func(PongMessage)
-	onPrivateMessage         func(PrivateMessage)
-	onWhisperMessage         func(WhisperMessage)
+	onUnsetMessage            func(UnsetMessage)
+	onModeratorError          func(error)
+	onConnected               func()
+	onCapabilities            func(CapMessage)
+	onClearChatMessage        func(ClearChatMessage)
+	onClearMsgMessage         func(ClearMsgMessage)
+	onGlobalUserstateMessage  func(GlobalUserstateMessage)
+	onHostTargetMessage       func(HostTargetMessage)
+	onNoticeMessage           func(NoticeMessage)
+	onReconnectMessage        func(ReconnectMessage)
+	onRoomstateMessage        func(RoomstateMessage)
+	onRoomSettingsChange      func(RoomSettingsChange)
+	onGap                     func(Gap)
+	onUserNoticeMessage       func(UsernoticeMessage)
+	onUserstateMessage        func(UserstateMessage)
+	onNamesMessage            func(NamesMessage)
+	onJoinMessage             func(JoinMessage)
+	onPartMessage             func(PartMessage)
+	onPingMessage             func(PingMessage)
+	onPongMessage             func(PongMessage)
+	onPrivateMessage          func(PrivateMessage)
+	onFirstMessage            func(PrivateMessage)
+	onChannelPointsRedemption func(PrivateMessage)
+	onHighlightedMessage      func(PrivateMessage)
+	onWhisperMessage          func(WhisperMessage)
+	onWelcomeMessage          func(WelcomeMessage)
+	onMotdMessage             func(MotdMessage)
+	onEndOfNamesMessage       func(EndOfNamesMessage)
+	onUnknownCommandMessage   func(UnknownCommandMessage)
 }
 
 // NewClient returns a new client using the provided config.
 func NewClient(c ClientConfig) *Client {
-	return &Client{
-		channels: make(map[string]bool),
-		config:   c,
-		inbound:  make(chan string, c.ReadBufferSize),
-		outbound: make(chan string, c.WriteBufferSize),
-		rcvdMsg:  make(chan struct{}),
+	var client = &Client{
+		channels:  make(map[string]channelState),
+		config:    c,
+		echoLogin: c.Identity.Username,
+		meIn:      make(map[string]Self),
+		inbound:   make(chan string, c.ReadBufferSize),
+		outbound:  make(chan string, c.WriteBufferSize),
+		rcvdMsg:   make(chan struct{}),
+		rooms:     make(map[string]RoomSettings),
 	}
+	client.moderator = ircModerator{client}
+	client.rLimiterMod = NewRateLimiter(RLimMsgMod)
+	return client
 }
 
 func (c *Client) callDone(err error) {
@@ -81,6 +122,14 @@ func (c *Client) connect(u url.URL) error {
 		return errReconnect
 	}
 
+	// Registration starts over on each connection.
+	c.rcvdWelcome = false
+	c.rcvdCapReply = len(c.config.Capabilities) == 0
+	c.capsMutex.Lock()
+	c.capabilities = nil
+	c.capsMutex.Unlock()
+	c.recentIDs = nil
+
 	// Waitgroup and context for goroutine control.
 	var wg = &sync.WaitGroup{}
 	var ctx, cancelFunc = context.WithCancel(context.Background())
@@ -93,12 +142,19 @@ func (c *Client) connect(u url.URL) error {
 		closeErr.update(errReason)
 	}
 
-	// Begin reading from c.conn in separate goroutine.
-	c.spawnReader(ctx, wg, closeErrCb)
+	// Begin reading from c.conn in separate goroutine, with its own context so a handover can stop it.
+	var readerCtx, cancelReader = context.WithCancel(ctx)
+	c.cancelReader = cancelReader
+	c.spawnReader(readerCtx, wg, c.conn, closeErrCb)
+
+	// A RECONNECT message hands over to a new connection instead of returning errReconnect, if enabled.
+	c.handover = func() {
+		c.spawnHandover(ctx, wg, u, closeErrCb)
+	}
 
 	// Send NICK, PASS, and CAP REQ.
 	// Sends in this goroutine before starting writer to prevent write conflicts.
-	err = c.sendConnectSequence()
+	err = c.sendConnectSequence(c.conn)
 	if err != nil {
 		closeErrCb(errReconnect)
 	}
@@ -112,8 +168,15 @@ func (c *Client) connect(u url.URL) error {
 		c.spawnPinger(ctx, wg, closeErrCb)
 	}
 
+	// Send chat messages with the Helix endpoint if set, off the writer goroutine.
+	c.spawnHelixChat(ctx, wg)
+
+	// Expire idle chatters if presence is tracked.
+	c.spawnPresenceExpiry(ctx, wg)
+
 	// Block and wait for a disconnect call or a connection error.
 	c.listenAndParse(ctx, closeErrCb)
+	c.markGaps(time.Now())
 
 	// Make sure reader, writer, and pinger have finished.
 	wg.Wait()
@@ -123,42 +186,105 @@ func (c *Client) connect(u url.URL) error {
 
 // disconnect sends a close message to the server and then closes the connection.
 func (c *Client) disconnect() bool {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
 	defer c.conn.Close()
 	return c.conn.WriteMessage(websocket.CloseMessage,
 		websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")) == nil
 }
 
-// sends joins using rate limiter if one is set
+// sends joins using rate limiter if one is set, batching channels that don't have to wait into one line.
 func (c *Client) joinChannels(channels []string) {
 	if channels == nil || len(channels) < 1 {
 		return
 	}
 
+	var batch []string
+	var flush = func() {
+		if len(batch) == 0 {
+			return
+		}
+		for _, line := range batchChannels("JOIN", batch) {
+			c.send(line)
+		}
+		c.channelsMutex.Lock()
+		for _, ch := range batch {
+			if state, ok := c.channels[ch]; ok {
+				state.sent = c.connected.get()
+				c.channels[ch] = state
+			}
+		}
+		c.channelsMutex.Unlock()
+		batch = nil
+	}
+
 	for _, ch := range channels {
 		if c.rLimiterJoins != nil {
-			c.rLimiterJoins.Wait()
+			// the limiter is charged per channel, but only sleep after sending what is already allowed
+			if wait := c.rLimiterJoins.take(); wait > 0 {
+				flush()
+				time.Sleep(wait)
+			}
 		}
 		if !c.connected.get() {
 			return
 		}
-		c.send("JOIN " + ch)
-		c.channelsMutex.Lock()
-		c.channels[ch] = c.connected.get()
-		c.channelsMutex.Unlock()
+		batch = append(batch, ch)
 	}
+	flush()
 }
 
 func (c *Client) onConnectedJoins() {
 	var channels = []string{}
 	c.channelsMutex.Lock()
-	for channel := range c.channels {
-		c.channels[channel] = false
+	for channel, state := range c.channels {
+		c.channels[channel] = channelState{waiters: state.waiters, lostAt: state.lostAt}
 		channels = append(channels, channel)
 	}
 	c.channelsMutex.Unlock()
 	c.joinChannels(channels)
 }
 
+// grants capabilities from a CAP ACK, and returns which configured capabilities have not been granted.
+func (c *Client) updateCapabilities(m CapMessage) []string {
+	c.capsMutex.Lock()
+	defer c.capsMutex.Unlock()
+
+	if m.Subcommand == "ACK" {
+		c.capabilities = append(c.capabilities, m.Capabilities...)
+	}
+
+	var missing []string
+	for _, requested := range c.config.Capabilities {
+		var granted bool
+		for _, capability := range c.capabilities {
+			if capability == requested {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			missing = append(missing, requested)
+		}
+	}
+	return missing
+}
+
+// once both 001 and the CAP reply have been handled, joins channels and calls onConnected.
+func (c *Client) checkRegistered() {
+	if !c.rcvdWelcome || !c.rcvdCapReply || c.connected.get() {
+		return
+	}
+	c.connected.set(true)
+	go c.onConnectedJoins()
+	// successful connection, reset the reconnect counter
+	c.reconnectCounter = 0
+
+	if c.handlers.onConnected != nil {
+		c.handlers.onConnected()
+	}
+}
+
 func (c *Client) listenAndParse(ctx context.Context, closeErrCb func(error)) {
 	for {
 		select {
@@ -176,6 +302,47 @@ func (c *Client) listenAndParse(ctx context.Context, closeErrCb func(error)) {
 	}
 }
 
+// merges a ROOMSTATE into the channel's settings and calls onRoomSettingsChange if anything changed.
+func (c *Client) updateRoomSettings(m RoomstateMessage) {
+	c.roomsMutex.Lock()
+	var before = c.rooms[m.Channel]
+	var after = before.Merge(m)
+	c.rooms[m.Channel] = after
+	c.roomsMutex.Unlock()
+
+	if before != after && c.handlers.onRoomSettingsChange != nil {
+		c.handlers.onRoomSettingsChange(RoomSettingsChange{
+			Channel: m.Channel,
+			Before:  before,
+			After:   after,
+		})
+	}
+}
+
+// maxLineLength is the longest IRC line allowed, not counting the trailing \r\n.
+const maxLineLength = 510
+
+// batchChannels packs channels into as few "command #a,#b" lines as fit in maxLineLength.
+func batchChannels(command string, channels []string) []string {
+	var lines []string
+	var line strings.Builder
+	for _, ch := range channels {
+		if line.Len() > 0 && line.Len()+1+len(ch) > maxLineLength {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+		if line.Len() == 0 {
+			line.WriteString(command + " " + ch)
+		} else {
+			line.WriteString("," + ch)
+		}
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
 func (c *Client) send(message string) {
 	select {
 	case c.outbound <- message:
@@ -186,20 +353,20 @@ func (c *Client) send(message string) {
 	}
 }
 
-func (c *Client) sendConnectSequence() (err error) {
+func (c *Client) sendConnectSequence(conn *websocket.Conn) (err error) {
 	var message string
 	message = "PASS " + c.config.Identity.Password
-	err = c.conn.WriteMessage(websocket.TextMessage, []byte(message+"\r\n"))
+	err = conn.WriteMessage(websocket.TextMessage, []byte(message+"\r\n"))
 	if err != nil {
 		return
 	}
 	message = "NICK " + c.config.Identity.Username
-	err = c.conn.WriteMessage(websocket.TextMessage, []byte(message+"\r\n"))
+	err = conn.WriteMessage(websocket.TextMessage, []byte(message+"\r\n"))
 	if err != nil {
 		return
 	}
 	message = "CAP REQ :" + strings.Join(c.config.Capabilities, " ")
-	err = c.conn.WriteMessage(websocket.TextMessage, []byte(message+"\r\n"))
+	err = conn.WriteMessage(websocket.TextMessage, []byte(message+"\r\n"))
 	return
 }
 
@@ -244,7 +411,7 @@ func (c *Client) spawnPinger(ctx context.Context, wg *sync.WaitGroup, closeErrCb
 	}()
 }
 
-func (c *Client) spawnReader(ctx context.Context, wg *sync.WaitGroup, closeErrCb func(error)) {
+func (c *Client) spawnReader(ctx context.Context, wg *sync.WaitGroup, conn *websocket.Conn, closeErrCb func(error)) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -255,8 +422,11 @@ func (c *Client) spawnReader(ctx context.Context, wg *sync.WaitGroup, closeErrCb
 				return
 			default:
 			}
-			_, received, err := c.conn.ReadMessage()
+			_, received, err := conn.ReadMessage()
 			if err != nil {
+				if ctx.Err() != nil { // closed on purpose, by a disconnect or handover
+					return
+				}
 				closeErrCb(errReconnect)
 				return
 			}
@@ -286,7 +456,22 @@ func (c *Client) spawnWriter(ctx context.Context, wg *sync.WaitGroup, closeErrCb
 				return
 
 			case message := <-c.outbound:
-				err := c.conn.WriteMessage(websocket.TextMessage, []byte(message+"\r\n"))
+				if !c.waitMessageLimit(ctx, message) {
+					c.outbound <- message // store for after reconnect
+					return
+				}
+				if c.helixChat != nil {
+					if m, ok := c.helixChat.chatMessage(message); ok {
+						select {
+						case c.helixChat.queue <- m:
+							continue
+						case <-ctx.Done():
+							c.outbound <- message // store for after reconnect
+							return
+						}
+					}
+				}
+				err := c.write(message)
 				if err != nil {
 					c.outbound <- message // store for after reconnect
 
@@ -297,3 +482,28 @@ func (c *Client) spawnWriter(ctx context.Context, wg *sync.WaitGroup, closeErrCb
 		}
 	}()
 }
+
+// reports whether an outbound line is a PRIVMSG, with or without tags.
+func isPrivmsg(message string) bool {
+	if strings.HasPrefix(message, "@") {
+		if i := strings.IndexByte(message, ' '); i != -1 {
+			message = message[i+1:]
+		}
+	}
+	return strings.HasPrefix(message, "PRIVMSG ")
+}
+
+// writes message to the current connection.
+func (c *Client) write(message string) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	return c.conn.WriteMessage(websocket.TextMessage, []byte(message+"\r\n"))
+}
+
+// waits on the message rate limiter if message is a PRIVMSG, returns false if ctx is done first.
+func (c *Client) waitMessageLimit(ctx context.Context, message string) bool {
+	if c.rLimiterMsgs == nil || !isPrivmsg(message) {
+		return true
+	}
+	return c.rLimiterMsgs.waitContext(ctx)
+}
@@ -2,7 +2,9 @@ package tmi
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestListenAndParse(t *testing.T) {
@@ -53,3 +55,94 @@ func TestListenAndParse(t *testing.T) {
 		t.Errorf("expected error: nil, got error: %v", closeErr.err)
 	}
 }
+
+func TestUpdateRoomSettings(t *testing.T) {
+	var c = NewClient(NewClientConfig("", ""))
+
+	var changes []RoomSettingsChange
+	c.OnRoomSettingsChange(func(change RoomSettingsChange) {
+		changes = append(changes, change)
+	})
+
+	var roomstates = []string{
+		"@emote-only=0;followers-only=-1;r9k=0;room-id=1;slow=0;subs-only=0 :tmi.twitch.tv ROOMSTATE #dallas",
+		"@room-id=1;slow=0 :tmi.twitch.tv ROOMSTATE #dallas",
+		"@room-id=1;slow=5 :tmi.twitch.tv ROOMSTATE #dallas",
+	}
+	for _, raw := range roomstates {
+		if err := c.handleIRCMessage(raw); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("number of changes: got %v, want %v", len(changes), 2)
+	}
+	if changes[0].Before != (RoomSettings{}) || changes[0].After != (RoomSettings{RoomID: "1"}) {
+		t.Errorf("first change: got %+v", changes[0])
+	}
+	if changes[1].Before.Slow != 0 || changes[1].After.Slow != time.Second*5 {
+		t.Errorf("second change: got %+v", changes[1])
+	}
+
+	rs, ok := c.RoomSettings("Dallas")
+	if !ok {
+		t.Fatalf("RoomSettings not found for #dallas")
+	}
+	if rs.Slow != time.Second*5 || rs.RoomID != "1" {
+		t.Errorf("RoomSettings: got %+v", rs)
+	}
+
+	c.Part("dallas")
+	if _, ok := c.RoomSettings("dallas"); ok {
+		t.Errorf("RoomSettings should be removed after Part")
+	}
+}
+
+func TestBatchChannels(t *testing.T) {
+	got := batchChannels("JOIN", []string{"#a", "#b", "#c"})
+	assertStringSlicesEqual(t, "lines", got, []string{"JOIN #a,#b,#c"})
+
+	var channels []string
+	for i := 0; i < 100; i++ {
+		channels = append(channels, "#channel_"+strings.Repeat("x", 10))
+	}
+	got = batchChannels("PART", channels)
+	var count int
+	for _, line := range got {
+		if len(line) > maxLineLength {
+			t.Errorf("line length %v is over %v", len(line), maxLineLength)
+		}
+		count += len(strings.Split(strings.TrimPrefix(line, "PART "), ","))
+	}
+	assertIntsEqual(t, "channels in lines", count, len(channels))
+	if len(got) < 2 {
+		t.Errorf("lines: got %v, want more than one", len(got))
+	}
+}
+
+func TestJoinChannelsBatched(t *testing.T) {
+	var c = NewClient(NewClientConfig("", ""))
+	c.SetJoinRateLimit(RateLimit{Burst: 3, Rate: time.Millisecond * 10})
+	c.connected.set(true)
+
+	var channels = []string{"#a", "#b", "#c", "#d", "#e"}
+	c.Join(channels...)
+
+	var joined []string
+	for len(joined) < len(channels) {
+		select {
+		case line := <-c.outbound:
+			if len(joined) == 0 {
+				assertStringsEqual(t, "first line", line, "JOIN #a,#b,#c")
+			}
+			joined = append(joined, strings.Split(strings.TrimPrefix(line, "JOIN "), ",")...)
+		case <-time.After(time.Second):
+			t.Fatalf("joined %v, want %v", joined, channels)
+		}
+	}
+	assertStringSlicesEqual(t, "joined", joined, channels)
+
+	c.Part("a", "b")
+	assertStringsEqual(t, "part line", <-c.outbound, "PART #a,#b")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,8 +15,20 @@ const (
 	twitchWSHost  = "irc-ws.chat.twitch.tv:80"
 )
 
+// ranges Twitch accepts for the durations of moderation commands.
+const (
+	minTimeoutDuration   = time.Second
+	maxTimeoutDuration   = time.Hour * 24 * 14
+	minSlowDuration      = time.Second * 3
+	maxSlowDuration      = time.Minute * 120
+	maxFollowersDuration = time.Hour * 24 * 90
+)
+
 var (
 	errReconnect = errors.New("reconnect")
+	// ErrCapabilitiesDenied is returned from Connect and in OnDone when the server does not grant all of the
+	// configured capabilities and RequireCapabilities is set in the config.
+	ErrCapabilitiesDenied = errors.New("capabilities denied")
 	// ErrDisconnectCalled is returned from Connect and in OnDone when the client calls disconnect.
 	ErrDisconnectCalled = errors.New("disconnect was called")
 	// ErrLoginFailure is returned from Connect and in OnDone when the client receives a NOTICE message about a login failure.
@@ -86,6 +99,13 @@ func (c *Client) Connect() error {
 	}
 }
 
+// Capabilities returns the capabilities the server granted on the current connection.
+func (c *Client) Capabilities() []string {
+	c.capsMutex.Lock()
+	defer c.capsMutex.Unlock()
+	return append([]string{}, c.capabilities...)
+}
+
 // Disconnect closes the connection to the server, and does not attempt to reconnect.
 func (c *Client) Disconnect() {
 	c.notifDisconnect.notify()
@@ -102,11 +122,12 @@ func (c *Client) Join(channels ...string) error {
 	for _, channel := range channels {
 		channel = formatChannel(channel)
 
-		connected, ok := c.channels[channel]
-		if !ok {
-			c.channels[channel] = false
+		var state = c.channels[channel]
+		if state.status == ChannelFailed {
+			state = channelState{waiters: state.waiters} // try again
 		}
-		if !connected {
+		c.channels[channel] = state
+		if !state.sent {
 			newJoins = append(newJoins, channel)
 		}
 	}
@@ -126,15 +147,31 @@ func (c *Client) Part(channels ...string) error {
 		return errors.New("channels was empty or nil")
 	}
 
+	var parts = make([]string, 0, len(channels))
 	for _, channel := range channels {
 		channel = formatChannel(channel)
+		parts = append(parts, channel)
 
 		c.channelsMutex.Lock()
 		delete(c.channels, channel)
 		c.channelsMutex.Unlock()
 
-		if c.connected.get() {
-			c.send("PART " + channel)
+		c.roomsMutex.Lock()
+		delete(c.rooms, channel)
+		c.roomsMutex.Unlock()
+
+		if c.presence != nil {
+			c.presence.forget(channel)
+		}
+		if c.history != nil {
+			c.history.forget(channel)
+		}
+		c.forgetSelfIn(channel)
+	}
+
+	if c.connected.get() {
+		for _, line := range batchChannels("PART", parts) {
+			c.send(line)
 		}
 	}
 
@@ -155,6 +192,21 @@ func (c *Client) Say(channel string, message string) {
 	}
 }
 
+// Reply sends a PRIVMSG message in channel as a reply to the message with parentMsgID.
+// parentMsgID for a PrivateMessage is PrivateMessage.ID.
+func (c *Client) Reply(channel, parentMsgID, message string) {
+	channel = formatChannel(channel)
+	var tags = "@reply-parent-msg-id=" + parentMsgID + " "
+
+	if len(message) < 500 {
+		c.send(tags + "PRIVMSG " + channel + " :" + message)
+		return
+	}
+	for _, m := range splitChatMessage(message) {
+		c.send(tags + "PRIVMSG " + channel + " :" + m)
+	}
+}
+
 // Action sends a message as a /me, or action, message.
 func (c *Client) Action(channel, message string) error {
 	if len(message) > 490 {
@@ -169,61 +221,118 @@ func (c *Client) Ban(channel, user, reason string) error {
 	if len(reason)+len(user) > 490 {
 		return errors.New("user + reason must be shorter than 490 characters")
 	}
-	if reason != "" {
-		c.Say(channel, "/ban "+user+" "+reason)
-		return nil
-	}
-	c.Say(channel, "/ban "+user)
-	return nil
+	return c.modCommand(channel, func() error {
+		return c.moderator.Ban(channel, user, reason)
+	})
 }
 
 // Unban unbans user from channel.
 func (c *Client) Unban(channel, user string) {
-	c.Say(channel, "/unban "+user)
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Unban(channel, user)
+	}))
 }
 
 // Clear clears all chat messages in channel.
 func (c *Client) Clear(channel string) {
-	c.Say(channel, "/clear")
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Clear(channel)
+	}))
 }
 
 // Color changes the color of the username currently logged in.
 func (c *Client) Color(color string) {
-	c.Say("#"+c.config.Identity.Username, "/color "+color)
+	c.reportModeratorErr(c.moderator.Color(color))
 }
 
 // Commercial starts a a commercial break in channel that is seconds long.
 // seconds should be 30, 60, 90, 120, 150, or 180.
 func (c *Client) Commercial(channel, seconds string) {
-	c.Say(channel, "/commercial "+seconds)
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Commercial(channel, seconds)
+	}))
+}
+
+// CommercialLength is the length of a commercial break.
+type CommercialLength int
+
+const (
+	// Commercial30s for a 30 second commercial break
+	Commercial30s CommercialLength = 30
+	// Commercial60s for a 60 second commercial break
+	Commercial60s CommercialLength = 60
+	// Commercial90s for a 90 second commercial break
+	Commercial90s CommercialLength = 90
+	// Commercial120s for a 120 second commercial break
+	Commercial120s CommercialLength = 120
+	// Commercial150s for a 150 second commercial break
+	Commercial150s CommercialLength = 150
+	// Commercial180s for a 180 second commercial break
+	Commercial180s CommercialLength = 180
+)
+
+// CommercialFor starts a commercial break in channel that is length long.
+func (c *Client) CommercialFor(channel string, length CommercialLength) error {
+	switch length {
+	case Commercial30s, Commercial60s, Commercial90s, Commercial120s, Commercial150s, Commercial180s:
+	default:
+		return errors.New("commercial length must be 30, 60, 90, 120, 150, or 180 seconds")
+	}
+	return c.modCommand(channel, func() error {
+		return c.moderator.Commercial(channel, strconv.Itoa(int(length)))
+	})
 }
 
 // Delete deletes a single message in channel identified by messageID.
 // messageID for a PrivateMessage is PrivateMessage.ID.
 // messageID for a ReplyParentMsg is ReplyParentMsg.ID.
 func (c *Client) Delete(channel, messageID string) {
-	c.Say(channel, "/delete "+messageID)
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Delete(channel, messageID)
+	}))
 }
 
 // EmoteOnly turns on emoteonly mode in channel.
 func (c *Client) EmoteOnly(channel string) {
-	c.Say(channel, "/emoteonly")
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.EmoteOnly(channel)
+	}))
 }
 
 // EmoteOnlyOff turns off emoteonly mode in channel.
 func (c *Client) EmoteOnlyOff(channel string) {
-	c.Say(channel, "/emoteonlyoff")
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.EmoteOnlyOff(channel)
+	}))
 }
 
 // Followers turns on followersonly mode in channel with duration being how long a
 // user must be following before they can send messages.
 func (c *Client) Followers(channel, duration string) {
-	c.Say(channel, "/followers "+duration)
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Followers(channel, duration)
+	}))
+}
+
+// FollowersFor turns on followersonly mode in channel with d being how long a user must be following
+// before they can send messages. d must be whole minutes between 0 and 3 months (90 days).
+func (c *Client) FollowersFor(channel string, d time.Duration) error {
+	if d < 0 || d > maxFollowersDuration {
+		return errors.New("followers duration must be between 0 and 3 months")
+	}
+	if d%time.Minute != 0 {
+		return errors.New("followers duration must be whole minutes")
+	}
+	return c.modCommand(channel, func() error {
+		return c.moderator.Followers(channel, strconv.Itoa(int(d/time.Minute))+"m")
+	})
 }
 
 // FollowersOff turns off followersonly mode in channel.
 func (c *Client) FollowersOff(channel string) {
-	c.Say(channel, "/followersoff")
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.FollowersOff(channel)
+	}))
 }
 
 // Host starts hosting target in channel. Trims off # from beginning of target.
@@ -242,22 +351,23 @@ func (c *Client) Marker(channel, description string) error {
 	if len(description) > 490 {
 		return errors.New("description must be shorter than 490 characters")
 	}
-	if description != "" {
-		c.Say(channel, "/marker "+description)
-		return nil
-	}
-	c.Say(channel, "/marker")
-	return nil
+	return c.modCommand(channel, func() error {
+		return c.moderator.Marker(channel, description)
+	})
 }
 
 // Mod makes user a moderator in channel.
 func (c *Client) Mod(channel, user string) {
-	c.Say(channel, "/mod "+user)
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Mod(channel, user)
+	}))
 }
 
 // Unmod makes user no longer a moderator in channel.
 func (c *Client) Unmod(channel, user string) {
-	c.Say(channel, "/unmod "+user)
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Unmod(channel, user)
+	}))
 }
 
 // Mods requests the list of mods for channel. Use OnNoticeMessage to get the result.
@@ -268,7 +378,9 @@ func (c *Client) Mods(channel string) {
 
 // R9kBeta turns on r9kbeta(uniquechat) mode in channel.
 func (c *Client) R9kBeta(channel string) {
-	c.Say(channel, "/r9kbeta")
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.R9kBeta(channel)
+	}))
 }
 
 // R9kMode turns on r9kbeta(uniquechat) mode in channel.
@@ -283,7 +395,9 @@ func (c *Client) Uniquechat(channel string) {
 
 // R9kBetaOff turns off r9kbeta(uniquechat) mode in channel.
 func (c *Client) R9kBetaOff(channel string) {
-	c.Say(channel, "/r9kbetaoff")
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.R9kBetaOff(channel)
+	}))
 }
 
 // R9kModeOff turns off r9kbeta(uniquechat) mode in channel.
@@ -299,56 +413,100 @@ func (c *Client) UniquechatOff(channel string) {
 // Raid starts a raid on channel to target. Trims off # from beginning of target.
 func (c *Client) Raid(channel, target string) {
 	target = strings.TrimPrefix(target, "#")
-	c.Say(channel, "/raid "+target)
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Raid(channel, target)
+	}))
 }
 
 // Unraid cancels a raid on channel.
 func (c *Client) Unraid(channel string) {
-	c.Say(channel, "/unraid")
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Unraid(channel)
+	}))
 }
 
 // Slow turns on slow mode in channel with seconds delay between users sending messages.
 func (c *Client) Slow(channel, seconds string) {
-	c.Say(channel, "/slow "+seconds)
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Slow(channel, seconds)
+	}))
+}
+
+// SlowFor turns on slow mode in channel with d delay between users sending messages.
+// d must be whole seconds between 3 seconds and 120 minutes.
+func (c *Client) SlowFor(channel string, d time.Duration) error {
+	if d < minSlowDuration || d > maxSlowDuration {
+		return errors.New("slow duration must be between 3 seconds and 120 minutes")
+	}
+	if d%time.Second != 0 {
+		return errors.New("slow duration must be whole seconds")
+	}
+	return c.modCommand(channel, func() error {
+		return c.moderator.Slow(channel, strconv.Itoa(int(d/time.Second)))
+	})
 }
 
 // SlowOff turns off slow mode in channel.
 func (c *Client) SlowOff(channel string) {
-	c.Say(channel, "/slowoff")
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.SlowOff(channel)
+	}))
 }
 
 // Subscribers turns on subscribers only mode in channel.
 func (c *Client) Subscribers(channel string) {
-	c.Say(channel, "/subscribers")
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Subscribers(channel)
+	}))
 }
 
 // SubscribersOff turns off subscribers only mode in channel.
 func (c *Client) SubscribersOff(channel string) {
-	c.Say(channel, "/subscribersoff")
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.SubscribersOff(channel)
+	}))
 }
 
 // Timeout prevents user in channel from chatting for seconds and clears their messsages.
 func (c *Client) Timeout(channel, user, seconds string) {
-	if seconds != "" {
-		c.Say(channel, "/timeout "+user+" "+seconds)
-		return
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Timeout(channel, user, seconds)
+	}))
+}
+
+// TimeoutFor prevents user in channel from chatting for d and clears their messages.
+// d must be whole seconds between 1 second and 2 weeks.
+func (c *Client) TimeoutFor(channel, user string, d time.Duration) error {
+	if d < minTimeoutDuration || d > maxTimeoutDuration {
+		return errors.New("timeout duration must be between 1 second and 2 weeks")
 	}
-	c.Say(channel, "/timeout "+user)
+	if d%time.Second != 0 {
+		return errors.New("timeout duration must be whole seconds")
+	}
+	return c.modCommand(channel, func() error {
+		return c.moderator.Timeout(channel, user, strconv.Itoa(int(d/time.Second)))
+	})
 }
 
 // Untimeout removes a timeout for user in channel.
 func (c *Client) Untimeout(channel, user string) {
-	c.Say(channel, "/untimeout "+user)
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.Untimeout(channel, user)
+	}))
 }
 
 // VIP makes user a vip in channel.
 func (c *Client) VIP(channel, user string) {
-	c.Say(channel, "/vip "+user)
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.VIP(channel, user)
+	}))
 }
 
 // UnVIP makes user no longer a vip in channel.
 func (c *Client) UnVIP(channel, user string) {
-	c.Say(channel, "/unvip "+user)
+	c.reportModeratorErr(c.modCommand(channel, func() error {
+		return c.moderator.UnVIP(channel, user)
+	}))
 }
 
 // VIPs requests the list of vips for channel. Use OnNoticeMessage to get the result.
@@ -357,11 +515,31 @@ func (c *Client) VIPs(channel string) {
 	c.Say(channel, "/vips")
 }
 
+// RoomSettings returns the current chat settings of channel, and false if no ROOMSTATE has been received for it.
+func (c *Client) RoomSettings(channel string) (RoomSettings, bool) {
+	channel = formatChannel(channel)
+	c.roomsMutex.Lock()
+	defer c.roomsMutex.Unlock()
+	var rs, ok = c.rooms[channel]
+	return rs, ok
+}
+
 // SetJoinRateLimit sets the RateLimiter for JOIN commands to settings in RateLimit.
 func (c *Client) SetJoinRateLimit(rl RateLimit) {
 	c.rLimiterJoins = NewRateLimiter(rl)
 }
 
+// SetMessageRateLimit sets the RateLimiter for PRIVMSG lines, including commands sent with Say, to settings in RateLimit.
+func (c *Client) SetMessageRateLimit(rl RateLimit) {
+	c.rLimiterMsgs = NewRateLimiter(rl)
+}
+
+// SetModeratorRateLimit sets the RateLimiter charged by every moderation command, which paces bulk moderation, to settings in RateLimit.
+// It is RLimMsgMod by default, and RLimHelixDefault after UseHelix.
+func (c *Client) SetModeratorRateLimit(rl RateLimit) {
+	c.rLimiterMod = NewRateLimiter(rl)
+}
+
 // UpdatePassword updates the password the client uses for authentication.
 func (c *Client) UpdatePassword(password string) {
 	c.config.Identity.SetPassword(password)
@@ -372,6 +550,11 @@ func (c *Client) OnDone(cb func(fatal error)) {
 	c.done = cb
 }
 
+// OnModeratorError sets the callback for when the Moderator fails a command that does not return an error, e.g. Unban.
+func (c *Client) OnModeratorError(cb func(error)) {
+	c.handlers.onModeratorError = cb
+}
+
 // OnUnsetMessage sets the callback for when an unrecognized, non-handled, or unparsable message type is received.
 func (c *Client) OnUnsetMessage(cb func(UnsetMessage)) {
 	c.handlers.onUnsetMessage = cb
@@ -382,6 +565,11 @@ func (c *Client) OnConnected(cb func()) {
 	c.handlers.onConnected = cb
 }
 
+// OnCapabilities sets the callback for when the server replies to the capability request with CAP ACK or NAK.
+func (c *Client) OnCapabilities(cb func(CapMessage)) {
+	c.handlers.onCapabilities = cb
+}
+
 // OnClearChatMessage sets the callback for when a CLEARCHAT message is received.
 func (c *Client) OnClearChatMessage(cb func(ClearChatMessage)) {
 	c.handlers.onClearChatMessage = cb
@@ -417,6 +605,16 @@ func (c *Client) OnRoomstateMessage(cb func(RoomstateMessage)) {
 	c.handlers.onRoomstateMessage = cb
 }
 
+// OnRoomSettingsChange sets the callback for when a ROOMSTATE message changes a channel's RoomSettings.
+func (c *Client) OnRoomSettingsChange(cb func(RoomSettingsChange)) {
+	c.handlers.onRoomSettingsChange = cb
+}
+
+// OnGap sets the callback for when a channel is rejoined after the connection was lost.
+func (c *Client) OnGap(cb func(Gap)) {
+	c.handlers.onGap = cb
+}
+
 // OnUserNoticeMessage sets the callback for when a USERNOTICE message is received.
 func (c *Client) OnUserNoticeMessage(cb func(UsernoticeMessage)) {
 	c.handlers.onUserNoticeMessage = cb
@@ -457,11 +655,46 @@ func (c *Client) OnPrivateMessage(cb func(PrivateMessage)) {
 	c.handlers.onPrivateMessage = cb
 }
 
+// OnFirstMessage sets the callback for when a PRIVMSG message is a user's first message in the channel.
+func (c *Client) OnFirstMessage(cb func(PrivateMessage)) {
+	c.handlers.onFirstMessage = cb
+}
+
+// OnChannelPointsRedemption sets the callback for when a PRIVMSG message is sent by redeeming a custom channel points reward.
+func (c *Client) OnChannelPointsRedemption(cb func(PrivateMessage)) {
+	c.handlers.onChannelPointsRedemption = cb
+}
+
+// OnHighlightedMessage sets the callback for when a PRIVMSG message is highlighted with channel points.
+func (c *Client) OnHighlightedMessage(cb func(PrivateMessage)) {
+	c.handlers.onHighlightedMessage = cb
+}
+
 // OnWhisperMessage sets the callback for when a WHISPER message is received.
 func (c *Client) OnWhisperMessage(cb func(WhisperMessage)) {
 	c.handlers.onWhisperMessage = cb
 }
 
+// OnWelcomeMessage sets the callback for when a 001, 002, 003, or 004 message is received after logging in.
+func (c *Client) OnWelcomeMessage(cb func(WelcomeMessage)) {
+	c.handlers.onWelcomeMessage = cb
+}
+
+// OnMotdMessage sets the callback for when a 375, 372, or 376 message of the day message is received.
+func (c *Client) OnMotdMessage(cb func(MotdMessage)) {
+	c.handlers.onMotdMessage = cb
+}
+
+// OnEndOfNamesMessage sets the callback for when a 366 message is received after a channel's NAMES messages.
+func (c *Client) OnEndOfNamesMessage(cb func(EndOfNamesMessage)) {
+	c.handlers.onEndOfNamesMessage = cb
+}
+
+// OnUnknownCommandMessage sets the callback for when a 421 message is received for a command the server did not recognize.
+func (c *Client) OnUnknownCommandMessage(cb func(UnknownCommandMessage)) {
+	c.handlers.onUnknownCommandMessage = cb
+}
+
 func formatChannel(channel string) string {
 	channel = strings.TrimSpace(channel)
 	if !strings.HasPrefix(channel, "#") {
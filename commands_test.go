@@ -208,6 +208,17 @@ func TestUpdatePassword(t *testing.T) {
 	}
 }
 
+func TestReply(t *testing.T) {
+	c := NewClient(NewClientConfig("", ""))
+	c.Reply("#channel", "abc-123", "hello")
+	want := "@reply-parent-msg-id=abc-123 PRIVMSG #channel :hello"
+	got := <-c.outbound
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	assertBoolsEqual(t, "isPrivmsg", isPrivmsg(got), true)
+}
+
 func TestAction(t *testing.T) {
 	tests := []struct {
 		in   string
@@ -326,6 +337,21 @@ func TestCommercial(t *testing.T) {
 	}
 }
 
+func TestCommercialFor(t *testing.T) {
+	c := NewClient(NewClientConfig("", ""))
+	if err := c.CommercialFor("#channel", Commercial90s); err != nil {
+		t.Error(err)
+	}
+	want := "PRIVMSG #channel :/commercial 90"
+	got := <-c.outbound
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if err := c.CommercialFor("#channel", 45); err == nil {
+		t.Errorf("expected an error for a 45 second commercial")
+	}
+}
+
 func TestDelete(t *testing.T) {
 	c := NewClient(NewClientConfig("", ""))
 	c.Delete("#channel", "1234-5678")
@@ -366,6 +392,31 @@ func TestFollowers(t *testing.T) {
 	}
 }
 
+func TestFollowersFor(t *testing.T) {
+	c := NewClient(NewClientConfig("", ""))
+	if err := c.FollowersFor("#channel", time.Hour*2); err != nil {
+		t.Error(err)
+	}
+	want := "PRIVMSG #channel :/followers 120m"
+	got := <-c.outbound
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if err := c.FollowersFor("#channel", 0); err != nil {
+		t.Error(err)
+	}
+	want = "PRIVMSG #channel :/followers 0m"
+	got = <-c.outbound
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for _, d := range []time.Duration{-time.Minute, time.Hour*24*90 + time.Minute, time.Second * 30, time.Minute + time.Second} {
+		if err := c.FollowersFor("#channel", d); err == nil {
+			t.Errorf("expected an error for %v", d)
+		}
+	}
+}
+
 func TestFollowersOff(t *testing.T) {
 	c := NewClient(NewClientConfig("", ""))
 	c.FollowersOff("#channel")
@@ -518,6 +569,23 @@ func TestSlow(t *testing.T) {
 	}
 }
 
+func TestSlowFor(t *testing.T) {
+	c := NewClient(NewClientConfig("", ""))
+	if err := c.SlowFor("#channel", time.Minute*2); err != nil {
+		t.Error(err)
+	}
+	want := "PRIVMSG #channel :/slow 120"
+	got := <-c.outbound
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for _, d := range []time.Duration{time.Second * 2, time.Minute*120 + time.Second, time.Millisecond * 3500} {
+		if err := c.SlowFor("#channel", d); err == nil {
+			t.Errorf("expected an error for %v", d)
+		}
+	}
+}
+
 func TestSlowOff(t *testing.T) {
 	c := NewClient(NewClientConfig("", ""))
 	c.SlowOff("#channel")
@@ -564,6 +632,28 @@ func TestTimeout(t *testing.T) {
 	}
 }
 
+func TestTimeoutFor(t *testing.T) {
+	c := NewClient(NewClientConfig("", ""))
+	if err := c.TimeoutFor("#channel", "user", time.Minute*10); err != nil {
+		t.Error(err)
+	}
+	want := "PRIVMSG #channel :/timeout user 600"
+	got := <-c.outbound
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for _, d := range []time.Duration{time.Millisecond * 500, time.Hour*24*14 + time.Second, time.Millisecond * 1500} {
+		if err := c.TimeoutFor("#channel", "user", d); err == nil {
+			t.Errorf("expected an error for %v", d)
+		}
+	}
+
+	c.handleIRCMessage("@badge-info=;badges=;color=;display-name=;emote-sets=0;mod=0;subscriber=0;user-type= :tmi.twitch.tv USERSTATE #plain")
+	if err := c.TimeoutFor("#plain", "user", time.Minute); err != ErrNotModerator {
+		t.Errorf("got %v, want %v", err, ErrNotModerator)
+	}
+}
+
 func TestUntimeout(t *testing.T) {
 	c := NewClient(NewClientConfig("", ""))
 	c.Untimeout("#channel", "user")
@@ -17,14 +17,28 @@ const (
 	CapMembership = "twitch.tv/membership"
 )
 
+// SharedChatMode for what a client does with messages sent in another channel of a shared chat session.
+type SharedChatMode int
+
+const (
+	// SharedChatTag passes the messages to the callbacks with Source.OtherChannel set
+	SharedChatTag SharedChatMode = iota
+	// SharedChatDrop does not pass the messages to the callbacks
+	SharedChatDrop
+)
+
 // ClientConfig holds how a client connects, reconnects, logs in as, and pinger behavior.
 type ClientConfig struct {
-	Connection      ConnectionConfig // how the client will connect and reconnect
-	Identity        IdentityConfig   // who the client logs in as
-	Pinger          PingConfig       // how often to ping, and timeout
-	Capabilities    []string         // which capabilites to request upon connection
-	ReadBufferSize  int              // channel buffer size for inbound messages
-	WriteBufferSize int              // channel buffer size for outbound messages
+	Connection          ConnectionConfig // how the client will connect and reconnect
+	Identity            IdentityConfig   // who the client logs in as
+	Pinger              PingConfig       // how often to ping, and timeout
+	Capabilities        []string         // which capabilites to request upon connection
+	RequireCapabilities bool             // if true, fail with ErrCapabilitiesDenied when the server does not grant all of Capabilities
+	CheermotePrefixes   []string         // cheermote names to look for in bits messages, DefaultCheermotePrefixes if nil
+	SharedChat          SharedChatMode   // whether to tag or drop messages from other channels in a shared chat session
+	IgnoreSelf          bool             // if true, drop PRIVMSG messages sent by the client's own user, e.g. on another connection
+	ReadBufferSize      int              // channel buffer size for inbound messages
+	WriteBufferSize     int              // channel buffer size for outbound messages
 }
 
 // ConnectionConfig holds reconnect settings and (in)secure server connection.
@@ -33,6 +47,7 @@ type ConnectionConfig struct {
 	Secure               bool          // if true, connect to to Twitch's secure server(port 443), otherwise insecure (port 80)
 	MaxReconnectAttempts int           // maximum number of attempts to reconnect when disconnected, -1 is infinite
 	MaxReconnectInterval time.Duration // maximum interval between reconnect attempts
+	Handover             bool          // if true, on a RECONNECT message open and join channels on a new connection before closing the old one
 }
 
 // IdentityConfig holds the username and password to log in with.
@@ -78,11 +93,13 @@ func NewClientConfig(username, password string) ClientConfig {
 // Secure               = true,
 // MaxReconnectAttempts = -1 (infinite),
 // MaxReconnectInterval = 30 seconds,
+// Handover             = false,
 func (c *ConnectionConfig) Default() {
 	c.Reconnect = true
 	c.Secure = true
 	c.MaxReconnectAttempts = -1
 	c.MaxReconnectInterval = time.Second * 30
+	c.Handover = false
 }
 
 // SetReconnectSettings sets how often and how many times the client
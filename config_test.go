@@ -7,11 +7,11 @@ import (
 )
 
 func TestNewClientConfig(t *testing.T) {
-	connection := ConnectionConfig{true, true, -1, time.Second * 30}
+	connection := ConnectionConfig{true, true, -1, time.Second * 30, false}
 	id := IdentityConfig{}
 	pinger := PingConfig{true, time.Minute, time.Second * 5}
 
-	want := &ClientConfig{connection, id, pinger, []string{CapTags, CapCommands, CapMembership}, 512, 512}
+	want := &ClientConfig{connection, id, pinger, []string{CapTags, CapCommands, CapMembership}, false, nil, SharedChatTag, false, 512, 512}
 	got := NewClientConfig("", "")
 
 	if want.Connection != got.Connection {
@@ -0,0 +1,214 @@
+package tmi
+
+import (
+	"context"
+	"time"
+)
+
+// DualClient reads channels on an anonymous connection and sends on an authenticated one,
+// so a busy reader never delays messages and moderation commands.
+//
+// The embedded Client is the writer: Say, Action, the moderation commands, and the connection callbacks
+// (OnConnected, OnUserstateMessage, OnWhisperMessage, ...) use it.
+// Join, Part, and the channel message callbacks (OnPrivateMessage, OnClearChatMessage, ...) use the reader.
+// OnNoticeMessage and OnUnsetMessage are set on both.
+//
+// The writer never joins channels, so it never receives a USERSTATE and MeIn has nothing for any channel.
+// The checks against the client's own channel state, such as ErrNotModerator, do not apply to a DualClient,
+// and commands are sent and left for the server to refuse.
+type DualClient struct {
+	*Client
+	done   func(error)
+	reader *Client
+}
+
+// NewDualClient returns a new DualClient. The writer uses config, and the reader uses config with an anonymous identity.
+func NewDualClient(config ClientConfig) *DualClient {
+	var readerConfig = config
+	readerConfig.Identity.Anonymous()
+
+	var d = &DualClient{
+		Client: NewClient(config),
+		reader: NewClient(readerConfig),
+	}
+	// The reader is logged in as justinfan, so it would see the writer's messages echoed back as anyone else's.
+	// Comparing against the writer's login lets the reader's IgnoreSelf drop them.
+	d.reader.echoLogin = config.Identity.Username
+	d.Client.notifDisconnect.reset()
+	d.reader.notifDisconnect.reset()
+	return d
+}
+
+// Connect connects the reader and the writer, and blocks until they are done.
+// When either one stops, the other is disconnected, and the error that stopped the first one is returned.
+func (d *DualClient) Connect() error {
+	var errs = make(chan error, 2)
+	for _, c := range []*Client{d.reader, d.Client} {
+		go func(c *Client) {
+			var err = c.Connect()
+			d.Disconnect()
+			errs <- err
+		}(c)
+	}
+	var err = <-errs
+	<-errs
+
+	if d.done != nil {
+		d.done(err)
+	}
+	return err
+}
+
+// Disconnect disconnects the reader and the writer.
+func (d *DualClient) Disconnect() {
+	d.reader.Disconnect()
+	d.Client.Disconnect()
+}
+
+// Reader returns the anonymous client channels are joined on.
+func (d *DualClient) Reader() *Client {
+	return d.reader
+}
+
+// Writer returns the authenticated client messages are sent on.
+func (d *DualClient) Writer() *Client {
+	return d.Client
+}
+
+// Join joins channels on the reader.
+func (d *DualClient) Join(channels ...string) error {
+	return d.reader.Join(channels...)
+}
+
+// JoinWait joins channels on the reader, and waits like Client.JoinWait.
+func (d *DualClient) JoinWait(ctx context.Context, channels ...string) ([]JoinResult, error) {
+	return d.reader.JoinWait(ctx, channels...)
+}
+
+// Part leaves channels on the reader.
+func (d *DualClient) Part(channels ...string) error {
+	return d.reader.Part(channels...)
+}
+
+// Channels returns the status of each channel joined on the reader.
+func (d *DualClient) Channels() map[string]ChannelStatus {
+	return d.reader.Channels()
+}
+
+// RoomSettings returns the merged settings of a channel joined on the reader.
+func (d *DualClient) RoomSettings(channel string) (RoomSettings, bool) {
+	return d.reader.RoomSettings(channel)
+}
+
+// TrackPresence starts tracking who is in chat for each channel joined on the reader, and returns the tracker.
+func (d *DualClient) TrackPresence(idle time.Duration) *Presence {
+	return d.reader.TrackPresence(idle)
+}
+
+// KeepHistory starts keeping the last size PRIVMSG messages of each channel joined on the reader, and returns the history.
+func (d *DualClient) KeepHistory(size int) *History {
+	return d.reader.KeepHistory(size)
+}
+
+// TrackUsers starts keeping up to capacity users seen in channels joined on the reader, and returns the directory.
+func (d *DualClient) TrackUsers(capacity int) *UserDirectory {
+	return d.reader.TrackUsers(capacity)
+}
+
+// SetJoinRateLimit sets the RateLimiter for the reader's JOIN commands to settings in RateLimit.
+func (d *DualClient) SetJoinRateLimit(rl RateLimit) {
+	d.reader.SetJoinRateLimit(rl)
+}
+
+// OnDone sets the callback function for when both the reader and the writer are done to cb.
+func (d *DualClient) OnDone(cb func(fatal error)) {
+	d.done = cb
+}
+
+// OnUnsetMessage sets the callback for when an unset message is received on either connection.
+func (d *DualClient) OnUnsetMessage(cb func(UnsetMessage)) {
+	d.reader.OnUnsetMessage(cb)
+	d.Client.OnUnsetMessage(cb)
+}
+
+// OnNoticeMessage sets the callback for when a NOTICE message is received on either connection.
+// Responses to commands are received on the writer, and notices about joining channels on the reader.
+func (d *DualClient) OnNoticeMessage(cb func(NoticeMessage)) {
+	d.reader.OnNoticeMessage(cb)
+	d.Client.OnNoticeMessage(cb)
+}
+
+// OnClearChatMessage sets the callback for when a CLEARCHAT message is received on the reader.
+func (d *DualClient) OnClearChatMessage(cb func(ClearChatMessage)) {
+	d.reader.OnClearChatMessage(cb)
+}
+
+// OnClearMsgMessage sets the callback for when a CLEARMSG message is received on the reader.
+func (d *DualClient) OnClearMsgMessage(cb func(ClearMsgMessage)) {
+	d.reader.OnClearMsgMessage(cb)
+}
+
+// OnHostTargetMessage sets the callback for when a HOSTTARGET message is received on the reader.
+func (d *DualClient) OnHostTargetMessage(cb func(HostTargetMessage)) {
+	d.reader.OnHostTargetMessage(cb)
+}
+
+// OnRoomstateMessage sets the callback for when a ROOMSTATE message is received on the reader.
+func (d *DualClient) OnRoomstateMessage(cb func(RoomstateMessage)) {
+	d.reader.OnRoomstateMessage(cb)
+}
+
+// OnRoomSettingsChange sets the callback for when a ROOMSTATE changes a channel's settings on the reader.
+func (d *DualClient) OnRoomSettingsChange(cb func(RoomSettingsChange)) {
+	d.reader.OnRoomSettingsChange(cb)
+}
+
+// OnGap sets the callback for when a channel is rejoined on the reader after its connection was lost.
+func (d *DualClient) OnGap(cb func(Gap)) {
+	d.reader.OnGap(cb)
+}
+
+// OnUserNoticeMessage sets the callback for when a USERNOTICE message is received on the reader.
+func (d *DualClient) OnUserNoticeMessage(cb func(UsernoticeMessage)) {
+	d.reader.OnUserNoticeMessage(cb)
+}
+
+// OnNamesMessage sets the callback for when a NAMES message is received on the reader.
+func (d *DualClient) OnNamesMessage(cb func(NamesMessage)) {
+	d.reader.OnNamesMessage(cb)
+}
+
+// OnEndOfNamesMessage sets the callback for when the end of a NAMES list is received on the reader.
+func (d *DualClient) OnEndOfNamesMessage(cb func(EndOfNamesMessage)) {
+	d.reader.OnEndOfNamesMessage(cb)
+}
+
+// OnJoinMessage sets the callback for when a JOIN message is received on the reader.
+func (d *DualClient) OnJoinMessage(cb func(JoinMessage)) {
+	d.reader.OnJoinMessage(cb)
+}
+
+// OnPartMessage sets the callback for when a PART message is received on the reader.
+func (d *DualClient) OnPartMessage(cb func(PartMessage)) {
+	d.reader.OnPartMessage(cb)
+}
+
+// OnPrivateMessage sets the callback for when a PRIVMSG message is received on the reader.
+func (d *DualClient) OnPrivateMessage(cb func(PrivateMessage)) {
+	d.reader.OnPrivateMessage(cb)
+}
+
+// OnFirstMessage sets the callback for when a chatter's first message in a channel is received on the reader.
+func (d *DualClient) OnFirstMessage(cb func(PrivateMessage)) {
+	d.reader.OnFirstMessage(cb)
+}
+
+// OnChannelPointsRedemption sets the callback for when a message redeeming a custom reward is received on the reader.
+func (d *DualClient) OnChannelPointsRedemption(cb func(PrivateMessage)) {
+	d.reader.OnChannelPointsRedemption(cb)
+}
+
+// OnHighlightedMessage sets the callback for when a highlighted message is received on the reader.
+func (d *DualClient) OnHighlightedMessage(cb func(PrivateMessage)) {
+	d.reader.OnHighlightedMessage(cb)
+}
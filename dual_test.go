@@ -0,0 +1,61 @@
+package tmi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewDualClient(t *testing.T) {
+	d := NewDualClient(NewClientConfig("me", "token"))
+
+	assertStringsEqual(t, "writer username", d.Writer().config.Identity.Username, "me")
+	assertStringsEqual(t, "writer password", d.Writer().config.Identity.Password, "oauth:token")
+	if !strings.HasPrefix(d.Reader().config.Identity.Username, "justinfan") {
+		t.Errorf("reader username: got %v, want justinfan...", d.Reader().config.Identity.Username)
+	}
+	if d.Reader() == d.Writer() {
+		t.Errorf("reader and writer should be separate clients")
+	}
+}
+
+func TestDualClientRouting(t *testing.T) {
+	d := NewDualClient(NewClientConfig("me", "token"))
+
+	d.Join("a", "b")
+	assertIntsEqual(t, "reader channels", len(d.Reader().Channels()), 2)
+	assertIntsEqual(t, "writer channels", len(d.Writer().Channels()), 0)
+	assertIntsEqual(t, "Channels()", len(d.Channels()), 2)
+
+	d.Part("a")
+	assertIntsEqual(t, "reader channels after part", len(d.Reader().Channels()), 1)
+
+	d.Say("b", "hello")
+	select {
+	case got := <-d.Writer().outbound:
+		assertStringsEqual(t, "Say", got, "PRIVMSG #b :hello")
+	default:
+		t.Errorf("Say was not sent on the writer")
+	}
+	if len(d.Reader().outbound) != 0 {
+		t.Errorf("Say was sent on the reader")
+	}
+}
+
+func TestDualClientHandlers(t *testing.T) {
+	d := NewDualClient(NewClientConfig("me", "token"))
+
+	var privmsgs, notices int
+	d.OnPrivateMessage(func(m PrivateMessage) { privmsgs++ })
+	d.OnNoticeMessage(func(m NoticeMessage) { notices++ })
+
+	if d.Writer().handlers.onPrivateMessage != nil {
+		t.Errorf("OnPrivateMessage should only be set on the reader")
+	}
+	d.Reader().handleIRCMessage(":user!user@user.tmi.twitch.tv PRIVMSG #a :hi")
+	assertIntsEqual(t, "privmsgs", privmsgs, 1)
+
+	for _, c := range []*Client{d.Reader(), d.Writer()} {
+		c.handleIRCMessage("@msg-id=no_permission :tmi.twitch.tv NOTICE #a :You don't have permission to perform that action.")
+	}
+	assertIntsEqual(t, "notices", notices, 2)
+}
@@ -0,0 +1,196 @@
+package tmi
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// FragmentType for the kind of content a Fragment holds.
+type FragmentType int
+
+const (
+	// FragmentText for plain text
+	FragmentText FragmentType = iota
+	// FragmentEmote for a Twitch emote, Fragment.EmoteID is set
+	FragmentEmote
+	// FragmentCheermote for a cheer like Cheer100, Fragment.Cheermote is set
+	FragmentCheermote
+	// FragmentMention for an @mention, Fragment.Mention is set
+	FragmentMention
+	// FragmentLink for a URL, Fragment.URL is set
+	FragmentLink
+)
+
+func (ft FragmentType) String() string {
+	switch ft {
+	case FragmentText:
+		return "text"
+	case FragmentEmote:
+		return "emote"
+	case FragmentCheermote:
+		return "cheermote"
+	case FragmentMention:
+		return "mention"
+	case FragmentLink:
+		return "link"
+	default:
+		return "unknown"
+	}
+}
+
+// Fragment is a piece of a message's text, in order, for rendering messages.
+type Fragment struct {
+	Type FragmentType `json:"type"`
+	Text string       `json:"text"` // the text the fragment covers, joining all Text gives the original message
+
+	Cheermote *Cheermote `json:"cheermote,omitempty"` // set for FragmentCheermote
+	EmoteID   string     `json:"emote-id,omitempty"`  // set for FragmentEmote
+	Mention   string     `json:"mention,omitempty"`   // lowercase login without the @, set for FragmentMention
+	URL       string     `json:"url,omitempty"`       // set for FragmentLink, http:// is added when missing
+}
+
+// Fragments splits the message's text into text, emote, cheermote, mention, and link fragments.
+func (m PrivateMessage) Fragments() []Fragment {
+	return fragmentText(m.Text, m.Emotes, m.Cheermotes)
+}
+
+// Fragments splits the whisper's text into text, emote, mention, and link fragments.
+func (m WhisperMessage) Fragments() []Fragment {
+	return fragmentText(m.Text, m.Emotes, nil)
+}
+
+// Fragments splits the notice's text into text, emote, mention, and link fragments.
+func (m UsernoticeMessage) Fragments() []Fragment {
+	return fragmentText(m.Text, m.Emotes, nil)
+}
+
+func fragmentText(text string, emotes []Emote, cheermotes []Cheermote) []Fragment {
+	var fragments []Fragment
+	if text == "" {
+		return fragments
+	}
+
+	var u16 = utf16.Encode([]rune(text))
+	var valid = func(p EmotePosition) bool {
+		return p.StartIdx >= 0 && p.StartIdx <= p.EndIdx && p.EndIdx < len(u16)
+	}
+
+	var spans []fragmentSpan
+	for _, emote := range emotes {
+		for _, p := range emote.Positions {
+			if valid(p) {
+				spans = append(spans, fragmentSpan{p, Fragment{Type: FragmentEmote, EmoteID: emote.ID}})
+			}
+		}
+	}
+	for i := range cheermotes {
+		if valid(cheermotes[i].Position) {
+			spans = append(spans, fragmentSpan{cheermotes[i].Position, Fragment{Type: FragmentCheermote, Cheermote: &cheermotes[i]}})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].StartIdx < spans[j].StartIdx
+	})
+
+	var idx int
+	for _, span := range spans {
+		if span.StartIdx < idx {
+			continue // overlapping positions
+		}
+		fragments = appendWords(fragments, string(utf16.Decode(u16[idx:span.StartIdx])))
+		span.fragment.Text = string(utf16.Decode(u16[span.StartIdx : span.EndIdx+1]))
+		fragments = append(fragments, span.fragment)
+		idx = span.EndIdx + 1
+	}
+	fragments = appendWords(fragments, string(utf16.Decode(u16[idx:])))
+
+	return fragments
+}
+
+// fragmentSpan is an emote or cheermote fragment and where it is in the text.
+type fragmentSpan struct {
+	EmotePosition
+	fragment Fragment
+}
+
+// appendWords appends fragments for text that has no emotes or cheermotes, merging plain text with the last fragment.
+func appendWords(fragments []Fragment, text string) []Fragment {
+	var plain strings.Builder
+	var flush = func() {
+		if plain.Len() > 0 {
+			fragments = appendPlain(fragments, plain.String())
+			plain.Reset()
+		}
+	}
+
+	for i, word := range strings.Split(text, " ") {
+		if i > 0 {
+			plain.WriteByte(' ')
+		}
+		if word == "" {
+			continue
+		}
+
+		var fragment, rest, ok = parseWord(word)
+		if !ok {
+			plain.WriteString(word)
+			continue
+		}
+		flush()
+		fragments = append(fragments, fragment)
+		plain.WriteString(rest)
+	}
+	flush()
+
+	return fragments
+}
+
+func appendPlain(fragments []Fragment, text string) []Fragment {
+	if n := len(fragments); n > 0 && fragments[n-1].Type == FragmentText {
+		fragments[n-1].Text += text
+		return fragments
+	}
+	return append(fragments, Fragment{Type: FragmentText, Text: text})
+}
+
+// parseWord returns the mention or link fragment at the start of word, and what is left of word after it.
+func parseWord(word string) (Fragment, string, bool) {
+	if strings.HasPrefix(word, "@") {
+		var end = 1
+		for end < len(word) && isLoginChar(word[end]) {
+			end++
+		}
+		if end > 1 {
+			return Fragment{
+				Type:    FragmentMention,
+				Text:    word[:end],
+				Mention: strings.ToLower(word[1:end]),
+			}, word[end:], true
+		}
+		return Fragment{}, "", false
+	}
+
+	var lower = strings.ToLower(word)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "www.") {
+		var link = strings.TrimRight(word, ".,!?;:)'\"")
+		if link == "" || strings.HasSuffix(link, "://") {
+			return Fragment{}, "", false
+		}
+		var url = link
+		if strings.HasPrefix(lower, "www.") {
+			url = "http://" + link
+		}
+		return Fragment{
+			Type: FragmentLink,
+			Text: link,
+			URL:  url,
+		}, word[len(link):], true
+	}
+
+	return Fragment{}, "", false
+}
+
+func isLoginChar(b byte) bool {
+	return b == '_' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,86 @@
+package tmi
+
+import (
+	"testing"
+)
+
+func TestPrivateMessageFragments(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Fragment
+	}{
+		{
+			"@emotes=25:0-4,12-16 :u!u@u.tmi.twitch.tv PRIVMSG #c :Kappa hello Kappa",
+			[]Fragment{
+				{Type: FragmentEmote, Text: "Kappa", EmoteID: "25"},
+				{Type: FragmentText, Text: " hello "},
+				{Type: FragmentEmote, Text: "Kappa", EmoteID: "25"},
+			},
+		},
+		{
+			// 😀 is two UTF-16 code units, so Kappa starts at 3
+			"@emotes=25:3-7 :u!u@u.tmi.twitch.tv PRIVMSG #c :😀 Kappa @SomeOne, see https://twitch.tv.",
+			[]Fragment{
+				{Type: FragmentText, Text: "😀 "},
+				{Type: FragmentEmote, Text: "Kappa", EmoteID: "25"},
+				{Type: FragmentText, Text: " "},
+				{Type: FragmentMention, Text: "@SomeOne", Mention: "someone"},
+				{Type: FragmentText, Text: ", see "},
+				{Type: FragmentLink, Text: "https://twitch.tv", URL: "https://twitch.tv"},
+				{Type: FragmentText, Text: "."},
+			},
+		},
+		{
+			"@bits=150;emotes= :u!u@u.tmi.twitch.tv PRIVMSG #c :cheer100 great stream Kappa50",
+			[]Fragment{
+				{Type: FragmentCheermote, Text: "cheer100", Cheermote: &Cheermote{"cheer", 100, EmotePosition{0, 7}}},
+				{Type: FragmentText, Text: " great stream "},
+				{Type: FragmentCheermote, Text: "Kappa50", Cheermote: &Cheermote{"Kappa", 50, EmotePosition{22, 28}}},
+			},
+		},
+		{
+			"@emotes= :u!u@u.tmi.twitch.tv PRIVMSG #c :cheer100 without bits at www.example.com",
+			[]Fragment{
+				{Type: FragmentText, Text: "cheer100 without bits at "},
+				{Type: FragmentLink, Text: "www.example.com", URL: "http://www.example.com"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		ircData, _ := parseIRCMessage(test.in)
+		got := parsePrivateMessage(ircData, nil).Fragments()
+		assertFragmentsEqual(t, got, test.want)
+	}
+}
+
+func TestWhisperMessageFragments(t *testing.T) {
+	ircData, _ := parseIRCMessage("@emotes=25:37-41 :bobby!bobby@bobby.tmi.twitch.tv WHISPER billy :hey look I'm a whisper with an emote Kappa")
+	got := parseWhisperMessage(ircData).Fragments()
+	assertFragmentsEqual(t, got, []Fragment{
+		{Type: FragmentText, Text: "hey look I'm a whisper with an emote "},
+		{Type: FragmentEmote, Text: "Kappa", EmoteID: "25"},
+	})
+}
+
+func TestParseEmotesUTF16(t *testing.T) {
+	got := parseEmotes("25:3-7", "😀 Kappa")
+	assertEmoteSlicesEqual(t, got, []Emote{{"25", "Kappa", []EmotePosition{{3, 7}}}})
+}
+
+func assertFragmentsEqual(t *testing.T, got, want []Fragment) {
+	if len(got) != len(want) {
+		t.Errorf("Fragments: len(got) %v, len(want) %v, got %+v", len(got), len(want), got)
+		return
+	}
+	for i := range got {
+		if got[i].Type != want[i].Type || got[i].Text != want[i].Text || got[i].EmoteID != want[i].EmoteID ||
+			got[i].Mention != want[i].Mention || got[i].URL != want[i].URL {
+			t.Errorf("Fragments[%v]: got %+v, want %+v", i, got[i], want[i])
+		}
+		if (got[i].Cheermote == nil) != (want[i].Cheermote == nil) ||
+			(got[i].Cheermote != nil && *got[i].Cheermote != *want[i].Cheermote) {
+			t.Errorf("Fragments[%v].Cheermote: got %+v, want %+v", i, got[i].Cheermote, want[i].Cheermote)
+		}
+	}
+}
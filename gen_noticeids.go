@@ -0,0 +1,245 @@
+//go:build ignore
+// +build ignore
+
+// gen_noticeids generates noticeids.go from the NOTICE msg-id table below.
+// The table follows https://dev.twitch.tv/docs/irc/msg-id
+// Run with: go generate
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	info             = "NoticeInfo"
+	success          = "NoticeCommandSuccess"
+	cmdError         = "NoticeCommandError"
+	permissionDenied = "NoticePermissionDenied"
+	rateLimited      = "NoticeRateLimited"
+	suspended        = "NoticeChannelSuspended"
+	banned           = "NoticeBanned"
+)
+
+var msgIDs = []struct {
+	id       string
+	category string
+}{
+	{"already_banned", cmdError},
+	{"already_emote_only_off", cmdError},
+	{"already_emote_only_on", cmdError},
+	{"already_followers_off", cmdError},
+	{"already_followers_on", cmdError},
+	{"already_r9k_off", cmdError},
+	{"already_r9k_on", cmdError},
+	{"already_slow_off", cmdError},
+	{"already_slow_on", cmdError},
+	{"already_subs_off", cmdError},
+	{"already_subs_on", cmdError},
+	{"autohost_receive", info},
+	{"bad_ban_admin", permissionDenied},
+	{"bad_ban_anon", permissionDenied},
+	{"bad_ban_broadcaster", permissionDenied},
+	{"bad_ban_mod", permissionDenied},
+	{"bad_ban_self", cmdError},
+	{"bad_ban_staff", permissionDenied},
+	{"bad_commercial_error", cmdError},
+	{"bad_delete_message_broadcaster", permissionDenied},
+	{"bad_delete_message_mod", permissionDenied},
+	{"bad_host_error", cmdError},
+	{"bad_host_hosting", cmdError},
+	{"bad_host_rate_exceeded", rateLimited},
+	{"bad_host_rejected", cmdError},
+	{"bad_host_self", cmdError},
+	{"bad_mod_banned", cmdError},
+	{"bad_mod_mod", cmdError},
+	{"bad_slow_duration", cmdError},
+	{"bad_timeout_admin", permissionDenied},
+	{"bad_timeout_anon", permissionDenied},
+	{"bad_timeout_broadcaster", permissionDenied},
+	{"bad_timeout_duration", cmdError},
+	{"bad_timeout_mod", permissionDenied},
+	{"bad_timeout_self", cmdError},
+	{"bad_timeout_staff", permissionDenied},
+	{"bad_unban_no_ban", cmdError},
+	{"bad_unhost_error", cmdError},
+	{"bad_unmod_mod", cmdError},
+	{"bad_unvip_grantee_not_vip", cmdError},
+	{"bad_vip_achievement_incomplete", cmdError},
+	{"bad_vip_grantee_already_vip", cmdError},
+	{"bad_vip_grantee_banned", cmdError},
+	{"bad_vip_max_vips_reached", cmdError},
+	{"ban_success", success},
+	{"cmds_available", info},
+	{"color_changed", success},
+	{"commercial_success", success},
+	{"delete_message_success", success},
+	{"delete_staff_message_success", success},
+	{"emote_only_off", success},
+	{"emote_only_on", success},
+	{"followers_off", success},
+	{"followers_on", success},
+	{"followers_on_zero", success},
+	{"host_off", info},
+	{"host_on", info},
+	{"host_receive", info},
+	{"host_receive_no_count", info},
+	{"host_target_went_offline", info},
+	{"hosts_remaining", info},
+	{"invalid_user", cmdError},
+	{"login_failure", permissionDenied},
+	{"mod_success", success},
+	{"msg_banned", banned},
+	{"msg_bad_characters", cmdError},
+	{"msg_channel_blocked", suspended},
+	{"msg_channel_suspended", suspended},
+	{"msg_duplicate", cmdError},
+	{"msg_emoteonly", cmdError},
+	{"msg_followersonly", cmdError},
+	{"msg_followersonly_followed", cmdError},
+	{"msg_followersonly_zero", cmdError},
+	{"msg_r9k", cmdError},
+	{"msg_ratelimit", rateLimited},
+	{"msg_rejected", cmdError},
+	{"msg_rejected_mandatory", cmdError},
+	{"msg_requires_verified_phone_number", permissionDenied},
+	{"msg_slowmode", rateLimited},
+	{"msg_subsonly", cmdError},
+	{"msg_suspended", banned},
+	{"msg_timedout", banned},
+	{"msg_verified_email", permissionDenied},
+	{"no_help", info},
+	{"no_mods", success},
+	{"no_permission", permissionDenied},
+	{"no_vips", success},
+	{"not_hosting", cmdError},
+	{"parse_error", info},
+	{"r9k_off", success},
+	{"r9k_on", success},
+	{"raid_error_already_raiding", cmdError},
+	{"raid_error_forbidden", permissionDenied},
+	{"raid_error_self", cmdError},
+	{"raid_error_too_many_viewers", cmdError},
+	{"raid_error_unexpected", cmdError},
+	{"raid_notice_mature", info},
+	{"raid_notice_restricted_chat", info},
+	{"room_mods", success},
+	{"slow_off", success},
+	{"slow_on", success},
+	{"subs_off", success},
+	{"subs_on", success},
+	{"timeout_no_timeout", cmdError},
+	{"timeout_success", success},
+	{"tos_ban", suspended},
+	{"turbo_only_color", permissionDenied},
+	{"unavailable_command", cmdError},
+	{"unban_success", success},
+	{"unmod_success", success},
+	{"unraid_error_no_active_raid", cmdError},
+	{"unraid_error_unexpected", cmdError},
+	{"unraid_success", success},
+	{"unrecognized_cmd", cmdError},
+	{"unsupported_chatrooms_cmd", cmdError},
+	{"untimeout_banned", cmdError},
+	{"untimeout_success", success},
+	{"unvip_success", success},
+	{"usage_ban", cmdError},
+	{"usage_clear", cmdError},
+	{"usage_color", cmdError},
+	{"usage_commercial", cmdError},
+	{"usage_disconnect", cmdError},
+	{"usage_delete", cmdError},
+	{"usage_emote_only_off", cmdError},
+	{"usage_emote_only_on", cmdError},
+	{"usage_followers_off", cmdError},
+	{"usage_followers_on", cmdError},
+	{"usage_help", cmdError},
+	{"usage_host", cmdError},
+	{"usage_marker", cmdError},
+	{"usage_me", cmdError},
+	{"usage_mod", cmdError},
+	{"usage_mods", cmdError},
+	{"usage_r9k_off", cmdError},
+	{"usage_r9k_on", cmdError},
+	{"usage_raid", cmdError},
+	{"usage_slow_off", cmdError},
+	{"usage_slow_on", cmdError},
+	{"usage_subs_off", cmdError},
+	{"usage_subs_on", cmdError},
+	{"usage_timeout", cmdError},
+	{"usage_unban", cmdError},
+	{"usage_unhost", cmdError},
+	{"usage_unmod", cmdError},
+	{"usage_unraid", cmdError},
+	{"usage_untimeout", cmdError},
+	{"usage_unvip", cmdError},
+	{"usage_user_color", cmdError},
+	{"usage_vip", cmdError},
+	{"usage_vips", cmdError},
+	{"usage_whisper", cmdError},
+	{"vip_success", success},
+	{"vips_success", success},
+	{"whisper_banned", banned},
+	{"whisper_banned_recipient", cmdError},
+	{"whisper_invalid_login", cmdError},
+	{"whisper_invalid_self", cmdError},
+	{"whisper_limit_per_min", rateLimited},
+	{"whisper_limit_per_sec", rateLimited},
+	{"whisper_restricted", permissionDenied},
+	{"whisper_restricted_recipient", cmdError},
+}
+
+// words that don't follow regular title casing in identifiers
+var initialisms = map[string]string{
+	"id":    "ID",
+	"r9k":   "R9k",
+	"tos":   "TOS",
+	"unvip": "UnVIP",
+	"vip":   "VIP",
+	"vips":  "VIPs",
+}
+
+func identifier(id string) string {
+	var b strings.Builder
+	b.WriteString("MsgID")
+	for _, word := range strings.Split(id, "_") {
+		if w, ok := initialisms[word]; ok {
+			b.WriteString(w)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+func main() {
+	var buf bytes.Buffer
+
+	buf.WriteString("// Code generated by gen_noticeids.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package tmi\n\n")
+	buf.WriteString("// NOTICE msg-id values, see https://dev.twitch.tv/docs/irc/msg-id\n")
+	buf.WriteString("// login_failure and parse_error are set by the parser, Twitch does not send them.\n")
+	buf.WriteString("const (\n")
+	for _, m := range msgIDs {
+		fmt.Fprintf(&buf, "%s NoticeMsgID = %q\n", identifier(m.id), m.id)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("var noticeCategories = map[NoticeMsgID]NoticeCategory{\n")
+	for _, m := range msgIDs {
+		fmt.Fprintf(&buf, "%s: %s,\n", identifier(m.id), m.category)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile("noticeids.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
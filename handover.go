@@ -0,0 +1,298 @@
+package tmi
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// handoverTimeout is how long a handover waits on the new connection to register, and to confirm channels after the JOINs are sent.
+	handoverTimeout = time.Second * 10
+	// recentIDsSize is how many message ids are remembered to drop duplicates after a handover.
+	recentIDsSize = 1024
+)
+
+// spawnHandover opens a new connection, rejoins channels on it, and only then swaps it in for c.conn and closes the old one.
+// Messages received on the new connection before the swap are passed to the handler, and duplicates are dropped by id.
+// If the new connection fails, the client falls back to a regular reconnect,
+// unless it failed to log in or was not granted required capabilities.
+// spawnHandover must be called from the handler's goroutine.
+func (c *Client) spawnHandover(ctx context.Context, wg *sync.WaitGroup, u url.URL, closeErrCb func(error)) {
+	if c.handingOver.get() {
+		return
+	}
+	c.handingOver.set(true)
+	if c.recentIDs == nil {
+		c.recentIDs = newRecentIDs(recentIDsSize)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer c.handingOver.set(false)
+
+		var conn, joined, err = c.openHandover(ctx, u)
+		if err != nil {
+			if ctx.Err() == nil && isFatal(err) {
+				closeErrCb(err)
+			} else if ctx.Err() == nil {
+				closeErrCb(errReconnect)
+			}
+			return
+		}
+
+		c.writeMutex.Lock()
+		if ctx.Err() != nil { // the writer has closed, or is about to close, the old connection
+			c.writeMutex.Unlock()
+			conn.Close()
+			return
+		}
+		var old = c.conn
+		c.conn = conn
+		c.writeMutex.Unlock()
+
+		c.cancelReader()
+		var readerCtx, cancelReader = context.WithCancel(ctx)
+		c.cancelReader = cancelReader
+		c.spawnReader(readerCtx, wg, conn, closeErrCb)
+
+		old.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		old.Close()
+
+		c.syncHandoverChannels(joined)
+	}()
+}
+
+// openHandover dials, registers, and joins channels on a new connection.
+// It returns the connection and the channels it joined.
+func (c *Client) openHandover(ctx context.Context, u url.URL) (*websocket.Conn, []string, error) {
+	var conn, _, err = websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// unblock reads if the client disconnects in the middle of the handover
+	var finished = make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-finished:
+		}
+	}()
+
+	var joined []string
+	conn.SetReadDeadline(time.Now().Add(handoverTimeout))
+	if err = c.sendConnectSequence(conn); err == nil {
+		joined, err = c.awaitHandover(ctx, conn)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	conn.SetReadDeadline(time.Time{})
+	return conn, joined, nil
+}
+
+// awaitHandover reads from conn until it is registered, then sends JOINs and reads until each channel is confirmed or refused.
+// Once the JOINs are sent, what is read is passed to the handler.
+// Like connect, it fails with ErrLoginFailure or ErrCapabilitiesDenied when the server refuses the login or required capabilities.
+func (c *Client) awaitHandover(ctx context.Context, conn *websocket.Conn) ([]string, error) {
+	var welcomed bool
+	var capReply = len(c.config.Capabilities) == 0
+	var capabilities []string
+	var joined []string
+	var pending map[string]bool // nil until the JOINs are sent
+
+	for {
+		if pending == nil && welcomed && capReply {
+			var err error
+			if joined, err = c.handoverJoins(conn); err != nil {
+				return nil, err
+			}
+			pending = make(map[string]bool, len(joined))
+			for _, channel := range joined {
+				pending[channel] = true
+			}
+			conn.SetReadDeadline(time.Now().Add(handoverTimeout))
+		}
+		if pending != nil && len(pending) == 0 {
+			break
+		}
+
+		_, received, err := conn.ReadMessage()
+		if err != nil {
+			return nil, err
+		}
+		for _, rawMessage := range strings.Split(string(received), "\r\n") {
+			if len(rawMessage) == 0 {
+				continue
+			}
+			var data, _ = parseIRCMessage(rawMessage)
+			switch data.Command {
+			case "PING":
+				if err = conn.WriteMessage(websocket.TextMessage, []byte("PONG :"+parsePingMessage(data).Text+"\r\n")); err != nil {
+					return nil, err
+				}
+				continue
+
+			case "001":
+				welcomed = true
+
+			case "CAP":
+				var capMessage = parseCapMessage(data)
+				if capMessage.Subcommand == "ACK" {
+					capabilities = append(capabilities, capMessage.Capabilities...)
+				}
+				if capMessage.Subcommand != "ACK" && capMessage.Subcommand != "NAK" {
+					break
+				}
+				if c.config.RequireCapabilities && !grantedAll(c.config.Capabilities, capabilities) {
+					return nil, ErrCapabilitiesDenied
+				}
+				capReply = true
+
+			case "NOTICE":
+				var noticeMessage, err = parseNoticeMessage(data)
+				if err != nil {
+					return nil, err
+				}
+				if _, ok := joinFailures[NoticeMsgID(noticeMessage.MsgID)]; ok {
+					delete(pending, noticeMessage.Channel)
+				}
+
+			case "ROOMSTATE":
+				delete(pending, parseRoomstateMessage(data).Channel)
+			}
+
+			if pending != nil {
+				select {
+				case c.inbound <- rawMessage:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+		}
+	}
+
+	c.capsMutex.Lock()
+	c.capabilities = capabilities
+	c.capsMutex.Unlock()
+	return joined, nil
+}
+
+// grantedAll reports whether every one of requested is in granted.
+func grantedAll(requested, granted []string) bool {
+	for _, r := range requested {
+		var ok bool
+		for _, g := range granted {
+			if g == r {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// handoverJoins sends JOINs on conn for the channels that have not failed, charging the join rate limiter like joinChannels.
+func (c *Client) handoverJoins(conn *websocket.Conn) ([]string, error) {
+	var channels []string
+	c.channelsMutex.Lock()
+	for channel, state := range c.channels {
+		if state.status != ChannelFailed {
+			channels = append(channels, channel)
+		}
+	}
+	c.channelsMutex.Unlock()
+
+	var batch []string
+	var flush = func() error {
+		for _, line := range batchChannels("JOIN", batch) {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(line+"\r\n")); err != nil {
+				return err
+			}
+		}
+		batch = nil
+		return nil
+	}
+
+	for _, channel := range channels {
+		if c.rLimiterJoins != nil {
+			if wait := c.rLimiterJoins.take(); wait > 0 {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+				time.Sleep(wait)
+			}
+		}
+		batch = append(batch, channel)
+	}
+	return channels, flush()
+}
+
+// syncHandoverChannels joins channels that were joined, and parts channels that were parted, during the handover.
+func (c *Client) syncHandoverChannels(joined []string) {
+	var handedOver = make(map[string]bool, len(joined))
+	var parts []string
+	c.channelsMutex.Lock()
+	for _, channel := range joined {
+		handedOver[channel] = true
+		if _, ok := c.channels[channel]; !ok {
+			parts = append(parts, channel)
+		}
+	}
+	var joins []string
+	for channel, state := range c.channels {
+		if !handedOver[channel] && state.status != ChannelFailed {
+			c.channels[channel] = channelState{waiters: state.waiters}
+			joins = append(joins, channel)
+		}
+	}
+	c.channelsMutex.Unlock()
+
+	for _, line := range batchChannels("PART", parts) {
+		c.send(line)
+	}
+	c.joinChannels(joins)
+}
+
+// recentIDs remembers the ids of the last messages handled.
+type recentIDs struct {
+	ids  map[string]struct{}
+	ring []string
+	next int
+}
+
+func newRecentIDs(size int) *recentIDs {
+	return &recentIDs{
+		ids:  make(map[string]struct{}, size),
+		ring: make([]string, size),
+	}
+}
+
+// seen reports whether id was already handled, and remembers it if not. Empty ids are never seen.
+func (r *recentIDs) seen(id string) bool {
+	if id == "" {
+		return false
+	}
+	if _, ok := r.ids[id]; ok {
+		return true
+	}
+	if old := r.ring[r.next]; old != "" {
+		delete(r.ids, old)
+	}
+	r.ring[r.next] = id
+	r.ids[id] = struct{}{}
+	r.next = (r.next + 1) % len(r.ring)
+	return false
+}
@@ -0,0 +1,154 @@
+package tmi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeServer registers each connection and runs the next of its scripts on it.
+type fakeServer struct {
+	mutex   sync.Mutex
+	scripts []func(conn *websocket.Conn)
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var conn, err = (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	s.mutex.Lock()
+	var script = s.scripts[0]
+	s.scripts = s.scripts[1:]
+	s.mutex.Unlock()
+
+	fakeRead(conn, "CAP REQ")
+	fakeWrite(conn, ":tmi.twitch.tv 001 me :Welcome, GLHF!")
+	fakeWrite(conn, ":tmi.twitch.tv CAP * ACK :twitch.tv/tags twitch.tv/commands twitch.tv/membership")
+	script(conn)
+}
+
+// fakeRead reads lines until one starts with prefix, and returns false if the connection is closed first.
+func fakeRead(conn *websocket.Conn, prefix string) bool {
+	for {
+		_, received, err := conn.ReadMessage()
+		if err != nil {
+			return false
+		}
+		if strings.HasPrefix(string(received), prefix) {
+			return true
+		}
+	}
+}
+
+func fakeWrite(conn *websocket.Conn, line string) {
+	conn.WriteMessage(websocket.TextMessage, []byte(line+"\r\n"))
+}
+
+func TestHandover(t *testing.T) {
+	var oldClosed = make(chan struct{})
+	var server = &fakeServer{scripts: []func(*websocket.Conn){
+		func(conn *websocket.Conn) {
+			fakeRead(conn, "JOIN #a")
+			fakeWrite(conn, ":tmi.twitch.tv ROOMSTATE #a")
+			fakeWrite(conn, ":tmi.twitch.tv RECONNECT")
+			fakeWrite(conn, "@id=1 :user!user@user.tmi.twitch.tv PRIVMSG #a :one")
+			fakeRead(conn, "never") // until the client closes it
+			close(oldClosed)
+		},
+		func(conn *websocket.Conn) {
+			fakeRead(conn, "JOIN #a")
+			fakeWrite(conn, "@id=1 :user!user@user.tmi.twitch.tv PRIVMSG #a :one")
+			fakeWrite(conn, ":tmi.twitch.tv ROOMSTATE #a")
+			fakeWrite(conn, "@id=2 :user!user@user.tmi.twitch.tv PRIVMSG #a :two")
+			fakeRead(conn, "never")
+		},
+	}}
+	var ts = httptest.NewServer(server)
+	defer ts.Close()
+	var u, _ = url.Parse(strings.Replace(ts.URL, "http", "ws", 1))
+
+	var config = NewClientConfig("me", "token")
+	config.Pinger.Enabled = false
+	config.Connection.Handover = true
+	var c = NewClient(config)
+	c.Join("a")
+
+	var received []string
+	c.OnPrivateMessage(func(m PrivateMessage) {
+		received = append(received, m.Text)
+		if m.Text == "two" {
+			select {
+			case <-oldClosed:
+			case <-time.After(time.Second):
+				t.Errorf("old connection was not closed after the handover")
+			}
+			c.Disconnect()
+		}
+	})
+
+	c.notifDisconnect.reset()
+	var done = make(chan error)
+	go func() { done <- c.connect(*u) }()
+	select {
+	case err := <-done:
+		if err != ErrDisconnectCalled {
+			t.Errorf("connect: got %v, want %v", err, ErrDisconnectCalled)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("handover did not finish")
+	}
+	assertStringSlicesEqual(t, "received", received, []string{"one", "two"})
+}
+
+func TestHandoverLoginFailure(t *testing.T) {
+	var server = &fakeServer{scripts: []func(*websocket.Conn){
+		func(conn *websocket.Conn) {
+			fakeWrite(conn, ":tmi.twitch.tv RECONNECT")
+			fakeRead(conn, "never")
+		},
+		func(conn *websocket.Conn) {
+			fakeWrite(conn, ":tmi.twitch.tv NOTICE * :Login authentication failed")
+			fakeRead(conn, "never")
+		},
+	}}
+	var ts = httptest.NewServer(server)
+	defer ts.Close()
+	var u, _ = url.Parse(strings.Replace(ts.URL, "http", "ws", 1))
+
+	var config = NewClientConfig("me", "token")
+	config.Pinger.Enabled = false
+	config.Connection.Handover = true
+	var c = NewClient(config)
+
+	c.notifDisconnect.reset()
+	var done = make(chan error)
+	go func() { done <- c.connect(*u) }()
+	select {
+	case err := <-done:
+		if err != ErrLoginFailure {
+			t.Errorf("connect: got %v, want %v", err, ErrLoginFailure)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("handover did not fail")
+	}
+}
+
+func TestRecentIDs(t *testing.T) {
+	var r = newRecentIDs(2)
+	assertBoolsEqual(t, "empty id", r.seen(""), false)
+	assertBoolsEqual(t, "first a", r.seen("a"), false)
+	assertBoolsEqual(t, "second a", r.seen("a"), true)
+	r.seen("b")
+	r.seen("c")
+	assertBoolsEqual(t, "a after it was forgotten", r.seen("a"), false)
+	assertBoolsEqual(t, "c", r.seen("c"), true)
+}
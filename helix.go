@@ -0,0 +1,159 @@
+package tmi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const helixBaseURL = "https://api.twitch.tv/helix"
+
+// HelixConfig holds what is needed to call the Twitch Helix API.
+type HelixConfig struct {
+	BaseURL    string       // defaults to https://api.twitch.tv/helix, e.g. an httptest server's URL in tests
+	ClientID   string       // client ID of the application the token was issued to
+	Token      string       // user access token, defaults to the client's Identity.Password without its oauth: prefix
+	HTTPClient *http.Client // defaults to a client with a 10 second timeout
+}
+
+// HelixError is an error response from the Helix API.
+type HelixError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+func (e *HelixError) Error() string {
+	if e.Message != "" {
+		return "helix: " + strconv.Itoa(e.Status) + ": " + e.Message
+	}
+	return "helix: " + strconv.Itoa(e.Status) + " " + http.StatusText(e.Status)
+}
+
+// helixClient calls the Helix API as the client's user, and caches user IDs looked up by login.
+type helixClient struct {
+	config   HelixConfig
+	ids      map[string]string // login to user ID
+	idsMutex sync.Mutex
+	login    string // the client's own login
+}
+
+func newHelixClient(config HelixConfig, identity IdentityConfig) *helixClient {
+	if config.BaseURL == "" {
+		config.BaseURL = helixBaseURL
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if config.Token == "" {
+		config.Token = strings.TrimPrefix(identity.Password, "oauth:")
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: time.Second * 10}
+	}
+	return &helixClient{
+		config: config,
+		ids:    make(map[string]string),
+		login:  strings.ToLower(identity.Username),
+	}
+}
+
+// do sends a request to path with query and body as JSON, and decodes the response into out if it is not nil.
+func (h *helixClient) do(method, path string, query url.Values, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		var b, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	var u = h.config.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var req, err = http.NewRequest(method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+h.config.Token)
+	req.Header.Set("Client-Id", h.config.ClientID)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := h.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var helixErr = &HelixError{}
+		json.NewDecoder(resp.Body).Decode(helixErr)
+		helixErr.Status = resp.StatusCode
+		return helixErr
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// userIDs returns the user ID of each login, looking up the ones that are not cached yet.
+func (h *helixClient) userIDs(logins ...string) ([]string, error) {
+	logins = append([]string(nil), logins...)
+	var ids = make([]string, len(logins))
+	var query = url.Values{}
+	h.idsMutex.Lock()
+	for i := range logins {
+		var login = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(logins[i], "#"), "@"))
+		logins[i] = login
+		if id, ok := h.ids[login]; ok {
+			ids[i] = id
+			continue
+		}
+		query.Add("login", login)
+	}
+	h.idsMutex.Unlock()
+
+	if len(query) > 0 {
+		var resp struct {
+			Data []struct {
+				ID    string `json:"id"`
+				Login string `json:"login"`
+			} `json:"data"`
+		}
+		if err := h.do(http.MethodGet, "/users", query, nil, &resp); err != nil {
+			return nil, err
+		}
+		h.idsMutex.Lock()
+		for _, user := range resp.Data {
+			h.ids[strings.ToLower(user.Login)] = user.ID
+		}
+		for i, login := range logins {
+			ids[i] = h.ids[login]
+		}
+		h.idsMutex.Unlock()
+	}
+
+	for i, id := range ids {
+		if id == "" {
+			return nil, errors.New("helix: no user with login " + logins[i])
+		}
+	}
+	return ids, nil
+}
+
+// channelIDs returns the user IDs of channel's broadcaster and of the client's own user.
+func (h *helixClient) channelIDs(channel string) (broadcasterID, userID string, err error) {
+	var ids []string
+	if ids, err = h.userIDs(channel, h.login); err != nil {
+		return "", "", err
+	}
+	return ids[0], ids[1], nil
+}
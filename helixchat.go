@@ -0,0 +1,196 @@
+package tmi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrChatDropped matches every ChatDropError using errors.Is.
+var ErrChatDropped = errors.New("chat message dropped")
+
+// ChatDropError is returned when the Send Chat Message endpoint did not send a message, with the reason it gave.
+// Besides ErrChatDropped, it matches the ErrNotice* value of its code's NoticeCategory with errors.Is,
+// e.g. ErrNoticeRateLimited for msg_ratelimit.
+type ChatDropError struct {
+	Channel string
+	Text    string      // the message that was dropped
+	Code    NoticeMsgID // drop reason code, e.g. msg_duplicate
+	Message string      // drop reason message
+}
+
+func (e *ChatDropError) Error() string {
+	if e.Message != "" {
+		return "chat message dropped: " + string(e.Code) + ": " + e.Message
+	}
+	return "chat message dropped: " + string(e.Code)
+}
+
+// Is reports whether target is ErrChatDropped or the ErrNotice* value for the category of the error's code.
+func (e *ChatDropError) Is(target error) bool {
+	return target == ErrChatDropped || (target != nil && noticeCategoryErrs[e.Code.Category()] == target)
+}
+
+// HelixChat sends the client's chat messages with the Send Chat Message endpoint instead of as PRIVMSG messages.
+// The token must have the user:write:chat scope.
+type HelixChat struct {
+	helix   *helixClient
+	mutex   sync.Mutex
+	onError func(error)
+	queue   chan helixChatMessage // messages that passed the rate limiter, waiting to be sent
+}
+
+type helixChatMessage struct {
+	channel, text, parentMsgID string
+	result                     chan error // receives the message's error instead of onError, set by SayWait and ReplyWait
+}
+
+// UseHelixChat makes the client send the messages of Say and Reply with the Send Chat Message endpoint, and returns the sender.
+// Messages still go through the outbound queue and the message rate limiter.
+// Actions and chat commands, which the endpoint cannot send, are still sent over IRC.
+// Use SayWait and ReplyWait to get the error of a message, which Say and Reply pass to OnError.
+// UseHelixChat should be called before Connect.
+func (c *Client) UseHelixChat(config HelixConfig) *HelixChat {
+	c.helixChat = &HelixChat{
+		helix: newHelixClient(config, c.config.Identity),
+		queue: make(chan helixChatMessage, c.config.WriteBufferSize),
+	}
+	return c.helixChat
+}
+
+// OnError sets the callback for when a message was not sent, with a *ChatDropError when the endpoint dropped it,
+// or the *HelixError or transport error of the request.
+func (h *HelixChat) OnError(cb func(error)) {
+	h.mutex.Lock()
+	h.onError = cb
+	h.mutex.Unlock()
+}
+
+// returns the message a PRIVMSG line from the outbound queue should be sent as, and whether it should be,
+// which it should not for actions and chat commands.
+func (h *HelixChat) chatMessage(message string) (helixChatMessage, bool) {
+	var data, err = parseIRCMessage(message)
+	if err != nil || data.Command != "PRIVMSG" || len(data.Params) < 2 {
+		return helixChatMessage{}, false
+	}
+	var channel, text = data.Params[0], data.Params[1]
+	if strings.HasPrefix(text, "/") || strings.HasPrefix(text, "\u0001") {
+		return helixChatMessage{}, false
+	}
+	return helixChatMessage{channel: channel, text: text, parentMsgID: data.Tags["reply-parent-msg-id"]}, true
+}
+
+// SayWait sends message in channel with the Send Chat Message endpoint like Say, and waits until it is sent or ctx is done.
+// It returns a *ChatDropError when the endpoint dropped the message, or the *HelixError or transport error of the request.
+// It requires UseHelixChat and a connected client, and waits on the message rate limiter like Say.
+// message must be shorter than 500 characters, and cannot be an action or a chat command.
+func (c *Client) SayWait(ctx context.Context, channel, message string) error {
+	return c.chatWait(ctx, channel, "", message)
+}
+
+// ReplyWait sends message in channel as a reply to the message with parentMsgID like Reply, and waits like SayWait.
+func (c *Client) ReplyWait(ctx context.Context, channel, parentMsgID, message string) error {
+	return c.chatWait(ctx, channel, parentMsgID, message)
+}
+
+func (c *Client) chatWait(ctx context.Context, channel, parentMsgID, message string) error {
+	var h = c.helixChat
+	if h == nil {
+		return errors.New("sending a message and waiting for its result requires UseHelixChat")
+	}
+	if len(message) >= 500 {
+		return errors.New("message must be shorter than 500 characters")
+	}
+	if strings.HasPrefix(message, "/") || strings.HasPrefix(message, "\u0001") {
+		return errors.New("actions and chat commands cannot be sent with the Send Chat Message endpoint")
+	}
+	if c.rLimiterMsgs != nil && !c.rLimiterMsgs.waitContext(ctx) {
+		return ctx.Err()
+	}
+
+	var m = helixChatMessage{channel: formatChannel(channel), text: message, parentMsgID: parentMsgID, result: make(chan error, 1)}
+	select {
+	case h.queue <- m:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-m.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sends the messages the writer queued until ctx is done, so a slow request does not hold up PONG, JOIN, and other IRC lines.
+// Messages still queued are sent after reconnect.
+func (c *Client) spawnHelixChat(ctx context.Context, wg *sync.WaitGroup) {
+	var h = c.helixChat
+	if h == nil {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case m := <-h.queue:
+				var err = h.sendChatMessage(m.channel, m.text, m.parentMsgID)
+				if m.result != nil {
+					m.result <- err
+					continue
+				}
+				h.mutex.Lock()
+				var onError = h.onError
+				h.mutex.Unlock()
+				if err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// sendChatMessage sends text in channel, as a reply to the message with parentMsgID if it is not empty.
+func (h *HelixChat) sendChatMessage(channel, text, parentMsgID string) error {
+	var broadcasterID, senderID, err = h.helix.channelIDs(channel)
+	if err != nil {
+		return err
+	}
+	var body = map[string]interface{}{
+		"broadcaster_id": broadcasterID,
+		"sender_id":      senderID,
+		"message":        text,
+	}
+	if parentMsgID != "" {
+		body["reply_parent_message_id"] = parentMsgID
+	}
+	var resp struct {
+		Data []struct {
+			MessageID  string `json:"message_id"`
+			IsSent     bool   `json:"is_sent"`
+			DropReason *struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"drop_reason"`
+		} `json:"data"`
+	}
+	if err = h.helix.do(http.MethodPost, "/chat/messages", nil, body, &resp); err != nil {
+		return err
+	}
+	if len(resp.Data) == 0 || resp.Data[0].IsSent {
+		return nil
+	}
+	var dropErr = &ChatDropError{Channel: channel, Text: text}
+	if reason := resp.Data[0].DropReason; reason != nil {
+		dropErr.Code = NoticeMsgID(reason.Code)
+		dropErr.Message = reason.Message
+	}
+	return dropErr
+}
@@ -0,0 +1,103 @@
+package tmi
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestHelixChat(t *testing.T) {
+	f, helixSrv := newFakeHelix(t)
+	defer helixSrv.Close()
+
+	var ircLines = make(chan string, 2)
+	var server = &fakeServer{scripts: []func(*websocket.Conn){
+		func(conn *websocket.Conn) {
+			for {
+				_, received, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				if line := strings.TrimSpace(string(received)); strings.HasPrefix(line, "PRIVMSG") {
+					ircLines <- line
+				}
+			}
+		},
+	}}
+	var ts = httptest.NewServer(server)
+	defer ts.Close()
+	var u, _ = url.Parse(strings.Replace(ts.URL, "http", "ws", 1))
+
+	var config = NewClientConfig("me", "oauth:token")
+	config.Pinger.Enabled = false
+	var c = NewClient(config)
+	var errs = make(chan error, 1)
+	c.UseHelixChat(HelixConfig{BaseURL: helixSrv.URL, ClientID: "client"}).OnError(func(err error) { errs <- err })
+	c.OnConnected(func() {
+		c.Say("chan", "hello")
+		c.Reply("chan", "parent-1", "hi")
+		c.Say("chan", "dup")
+		c.Action("chan", "waves")
+		c.Say("chan", "/help")
+	})
+
+	c.notifDisconnect.reset()
+	var done = make(chan error)
+	go func() { done <- c.connect(*u) }()
+
+	for _, want := range []string{"PRIVMSG #chan :\u0001ACTION waves\u0001", "PRIVMSG #chan :/help"} {
+		select {
+		case line := <-ircLines:
+			assertStringsEqual(t, "IRC line", line, want)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("%q was not sent over IRC", want)
+		}
+	}
+	select {
+	case err := <-errs:
+		var dropErr *ChatDropError
+		if !errors.As(err, &dropErr) || dropErr.Code != MsgIDMsgDuplicate || dropErr.Text != "dup" {
+			t.Errorf("got error %v, want a msg_duplicate ChatDropError", err)
+		}
+		if !errors.Is(err, ErrChatDropped) {
+			t.Errorf("errors.Is(%v, ErrChatDropped) is false", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Errorf("the dropped message was not reported")
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	var dropErr *ChatDropError
+	if err := c.SayWait(ctx, "chan", "dup"); !errors.As(err, &dropErr) || dropErr.Code != MsgIDMsgDuplicate {
+		t.Errorf("SayWait: got error %v, want a msg_duplicate ChatDropError", err)
+	}
+	if err := c.ReplyWait(ctx, "chan", "parent-2", "thanks"); err != nil {
+		t.Errorf("ReplyWait: got error %v, want nil", err)
+	}
+	if err := c.SayWait(ctx, "chan", "/help"); err == nil {
+		t.Errorf("SayWait: expected an error for a chat command")
+	}
+	if len(errs) != 0 {
+		t.Errorf("SayWait and ReplyWait errors should not be passed to OnError")
+	}
+
+	f.mutex.Lock()
+	assertStringSlicesEqual(t, "requests", f.requests, []string{
+		`POST /chat/messages? {"broadcaster_id":"2","message":"hello","sender_id":"1"}`,
+		`POST /chat/messages? {"broadcaster_id":"2","message":"hi","reply_parent_message_id":"parent-1","sender_id":"1"}`,
+		`POST /chat/messages? {"broadcaster_id":"2","message":"dup","sender_id":"1"}`,
+		`POST /chat/messages? {"broadcaster_id":"2","message":"dup","sender_id":"1"}`,
+		`POST /chat/messages? {"broadcaster_id":"2","message":"thanks","reply_parent_message_id":"parent-2","sender_id":"1"}`,
+	})
+	f.mutex.Unlock()
+
+	c.Disconnect()
+	<-done
+}
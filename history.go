@@ -0,0 +1,216 @@
+package tmi
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// RemovalReason for why a message in the history was removed from chat.
+type RemovalReason int
+
+const (
+	// NotRemoved for a message that is still in chat
+	NotRemoved RemovalReason = iota
+	// RemovedDeleted for a message deleted by a CLEARMSG
+	RemovedDeleted
+	// RemovedTimeout for a message from a user who was timed out
+	RemovedTimeout
+	// RemovedBan for a message from a user who was banned
+	RemovedBan
+	// RemovedClear for a message removed when the chat was cleared
+	RemovedClear
+)
+
+func (rr RemovalReason) String() string {
+	switch rr {
+	case NotRemoved:
+		return "not removed"
+	case RemovedDeleted:
+		return "deleted"
+	case RemovedTimeout:
+		return "timeout"
+	case RemovedBan:
+		return "ban"
+	case RemovedClear:
+		return "clear"
+	default:
+		return "unknown"
+	}
+}
+
+// HistoryEntry is a PRIVMSG message kept in a channel's history.
+type HistoryEntry struct {
+	Message   PrivateMessage `json:"message"`
+	Removed   RemovalReason  `json:"removed"`
+	RemovedAt time.Time      `json:"removed-at"` // zero if not removed
+}
+
+// HistoryRemoval when a CLEARMSG or CLEARCHAT message removes messages kept in a channel's history.
+type HistoryRemoval struct {
+	Channel  string         `json:"channel"`
+	Reason   RemovalReason  `json:"reason"`
+	Username string         `json:"username"` // user whose messages were removed, empty for RemovedClear
+	Duration time.Duration  `json:"duration"` // duration of the timeout for RemovedTimeout
+	Entries  []HistoryEntry `json:"entries"`  // the removed messages, oldest first
+	Data     IRCData        `json:"data"`     // the CLEARMSG or CLEARCHAT message
+}
+
+// History keeps the most recent PRIVMSG messages of each channel.
+type History struct {
+	channels  map[string]*historyRing
+	mutex     sync.Mutex
+	onRemoved func(HistoryRemoval)
+	size      int
+}
+
+// historyRing holds a channel's last size entries, next is where the next entry goes.
+type historyRing struct {
+	entries []HistoryEntry
+	next    int
+}
+
+// KeepHistory starts keeping the last size PRIVMSG messages of each channel, and returns the history.
+// Size less than 1 is treated as 1. KeepHistory should be called before Connect.
+func (c *Client) KeepHistory(size int) *History {
+	if size < 1 {
+		size = 1
+	}
+	c.history = &History{
+		channels: make(map[string]*historyRing),
+		size:     size,
+	}
+	return c.history
+}
+
+// Messages returns the entries kept for channel, oldest first.
+func (h *History) Messages(channel string) []HistoryEntry {
+	channel = formatChannel(channel)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.entries(channel, func(*HistoryEntry) bool { return true })
+}
+
+// ByID returns the entry of the message with id in channel, and false if it is not kept.
+func (h *History) ByID(channel, id string) (HistoryEntry, bool) {
+	channel = formatChannel(channel)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	var entries = h.entries(channel, func(e *HistoryEntry) bool { return e.Message.ID == id })
+	if len(entries) == 0 {
+		return HistoryEntry{}, false
+	}
+	return entries[0], true
+}
+
+// ByUser returns the entries of the messages from the user with login username in channel, oldest first.
+func (h *History) ByUser(channel, username string) []HistoryEntry {
+	channel = formatChannel(channel)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.entries(channel, func(e *HistoryEntry) bool { return strings.EqualFold(e.Message.User.Name, username) })
+}
+
+// OnRemoved sets the callback for when a CLEARMSG or CLEARCHAT message removes messages kept in the history.
+func (h *History) OnRemoved(cb func(HistoryRemoval)) {
+	h.mutex.Lock()
+	h.onRemoved = cb
+	h.mutex.Unlock()
+}
+
+// add keeps m, replacing the channel's oldest entry when the history is full.
+func (h *History) add(m PrivateMessage) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var ring, ok = h.channels[m.Channel]
+	if !ok {
+		ring = &historyRing{}
+		h.channels[m.Channel] = ring
+	}
+	if len(ring.entries) < h.size {
+		ring.entries = append(ring.entries, HistoryEntry{Message: m})
+		return
+	}
+	ring.entries[ring.next] = HistoryEntry{Message: m}
+	ring.next = (ring.next + 1) % len(ring.entries)
+}
+
+// forget drops the entries kept for channel, for when the client parts it.
+func (h *History) forget(channel string) {
+	h.mutex.Lock()
+	delete(h.channels, channel)
+	h.mutex.Unlock()
+}
+
+// clearMsg marks the deleted message as removed.
+func (h *History) clearMsg(m ClearMsgMessage) {
+	h.remove(HistoryRemoval{Channel: m.Channel, Reason: RemovedDeleted, Username: m.Login, Data: m.Data},
+		func(e *HistoryEntry) bool { return e.Message.ID == m.TargetMsgID })
+}
+
+// clearChat marks the messages of the timed out or banned user, or all messages when the chat was cleared, as removed.
+// A timeout or ban from another channel in a shared chat session only removes messages that came from that channel.
+func (h *History) clearChat(m ClearChatMessage) {
+	var removal = HistoryRemoval{Channel: m.Channel, Reason: RemovedClear, Data: m.Data}
+	if m.Target == "" {
+		h.remove(removal, func(*HistoryEntry) bool { return true })
+		return
+	}
+
+	removal.Username = m.Target
+	removal.Reason = RemovedBan
+	if m.BanDuration > 0 {
+		removal.Reason = RemovedTimeout
+		removal.Duration = m.BanDuration
+	}
+	h.remove(removal, func(e *HistoryEntry) bool {
+		if !strings.EqualFold(e.Message.User.Name, m.Target) {
+			return false
+		}
+		if m.Source != nil && m.Source.OtherChannel {
+			return e.Message.Source != nil && e.Message.Source.RoomID == m.Source.RoomID
+		}
+		return true
+	})
+}
+
+// remove marks the entries that match and are not removed yet, and calls onRemoved with them.
+func (h *History) remove(removal HistoryRemoval, match func(*HistoryEntry) bool) {
+	var now = time.Now()
+	h.mutex.Lock()
+	var ring = h.channels[removal.Channel]
+	if ring != nil {
+		for i := range ring.entries {
+			var e = &ring.entries[(ring.next+i)%len(ring.entries)]
+			if e.Removed == NotRemoved && match(e) {
+				e.Removed = removal.Reason
+				e.RemovedAt = now
+				removal.Entries = append(removal.Entries, *e)
+			}
+		}
+	}
+	var onRemoved = h.onRemoved
+	h.mutex.Unlock()
+
+	if len(removal.Entries) > 0 && onRemoved != nil {
+		onRemoved(removal)
+	}
+}
+
+// entries returns the channel's entries that match, oldest first.
+// entries requires that the mutex lock is held for the History.
+func (h *History) entries(channel string, match func(*HistoryEntry) bool) []HistoryEntry {
+	var ring = h.channels[channel]
+	if ring == nil {
+		return nil
+	}
+	var entries []HistoryEntry
+	for i := range ring.entries {
+		var e = &ring.entries[(ring.next+i)%len(ring.entries)]
+		if match(e) {
+			entries = append(entries, *e)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,75 @@
+package tmi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	c := NewClient(NewClientConfig("", ""))
+	h := c.KeepHistory(3)
+
+	var removals []HistoryRemoval
+	h.OnRemoved(func(r HistoryRemoval) { removals = append(removals, r) })
+
+	for _, raw := range []string{
+		"@id=1 :alice!alice@alice.tmi.twitch.tv PRIVMSG #a :one",
+		"@id=2 :bob!bob@bob.tmi.twitch.tv PRIVMSG #a :two",
+		"@id=3 :alice!alice@alice.tmi.twitch.tv PRIVMSG #a :three",
+		"@id=4 :alice!alice@alice.tmi.twitch.tv PRIVMSG #a :four",
+		"@id=5 :carol!carol@carol.tmi.twitch.tv PRIVMSG #b :five",
+	} {
+		c.handleIRCMessage(raw)
+	}
+
+	var texts = func(entries []HistoryEntry) []string {
+		var texts []string
+		for _, e := range entries {
+			texts = append(texts, e.Message.Text)
+		}
+		return texts
+	}
+	assertStringSlicesEqual(t, "Messages", texts(h.Messages("a")), []string{"two", "three", "four"})
+	assertStringSlicesEqual(t, "ByUser", texts(h.ByUser("#a", "Alice")), []string{"three", "four"})
+	if _, ok := h.ByID("a", "1"); ok {
+		t.Errorf("ByID: message 1 should have been replaced")
+	}
+	if e, ok := h.ByID("a", "2"); !ok || e.Message.Text != "two" {
+		t.Errorf("ByID: got %v, %v, want two", e.Message.Text, ok)
+	}
+
+	c.handleIRCMessage("@login=bob;target-msg-id=2 :tmi.twitch.tv CLEARMSG #a :two")
+	c.handleIRCMessage("@ban-duration=600 :tmi.twitch.tv CLEARCHAT #a :alice")
+	c.handleIRCMessage("@ban-duration=600 :tmi.twitch.tv CLEARCHAT #a :alice") // nothing left to remove
+	c.handleIRCMessage(":tmi.twitch.tv CLEARCHAT #b")
+
+	assertIntsEqual(t, "len(removals)", len(removals), 3)
+	var want = []struct {
+		channel  string
+		reason   RemovalReason
+		username string
+		texts    []string
+	}{
+		{"#a", RemovedDeleted, "bob", []string{"two"}},
+		{"#a", RemovedTimeout, "alice", []string{"three", "four"}},
+		{"#b", RemovedClear, "", []string{"five"}},
+	}
+	for i, w := range want {
+		assertStringsEqual(t, "Channel", removals[i].Channel, w.channel)
+		if removals[i].Reason != w.reason {
+			t.Errorf("Reason: got %v, want %v", removals[i].Reason, w.reason)
+		}
+		assertStringsEqual(t, "Username", removals[i].Username, w.username)
+		assertStringSlicesEqual(t, "Entries", texts(removals[i].Entries), w.texts)
+	}
+	assertDurationsEqual(t, "Duration", removals[1].Duration, time.Minute*10)
+
+	for _, e := range h.Messages("a") {
+		if e.Removed == NotRemoved || e.RemovedAt.IsZero() {
+			t.Errorf("%v should have been marked removed", e.Message.Text)
+		}
+	}
+
+	c.Part("a")
+	assertIntsEqual(t, "Messages after part", len(h.Messages("a")), 0)
+}
@@ -2,6 +2,8 @@ package tmi
 
 import (
 	"errors"
+	"strings"
+	"time"
 )
 
 var (
@@ -14,6 +16,12 @@ func (c *Client) handleIRCMessage(rawMessage string) error {
 	if errParseIRC != nil {
 		return c.unsetHandler(data)
 	}
+	if c.recentIDs != nil && c.recentIDs.seen(data.Tags["id"]) {
+		return nil // already received on the other connection during a handover
+	}
+	if c.users != nil {
+		c.users.observe(data, time.Now())
+	}
 
 	var err = c.handleIRCData(data)
 	if err == errUnsetIRCCommand || err == errUnrecognizedIRCCommand {
@@ -32,31 +40,81 @@ func (c *Client) unsetHandler(data IRCData) error {
 func (c *Client) handleIRCData(data IRCData) error {
 	switch data.Command {
 	case "001": // RPL_WELCOME        RFC2812 ; "Welcome, GLHF"
-		c.connected.set(true)
-		go c.onConnectedJoins()
-		// successful connection, reset the reconnect counter
-		c.reconnectCounter = 0
+		if c.handlers.onWelcomeMessage != nil {
+			c.handlers.onWelcomeMessage(parseWelcomeMessage(data))
+		}
+		c.rcvdWelcome = true
+		c.checkRegistered()
+		return nil
+
+	case "002", "003", "004": // RPL_YOURHOST, RPL_CREATED, RPL_MYINFO RFC2812 ; rest of the post registration greeting
+		if c.handlers.onWelcomeMessage != nil {
+			c.handlers.onWelcomeMessage(parseWelcomeMessage(data))
+		}
+		return nil
+
+	case "375", "372", "376": // RPL_MOTDSTART, RPL_MOTD, RPL_ENDOFMOTD RFC1459 ; message of the day
+		if c.handlers.onMotdMessage != nil {
+			c.handlers.onMotdMessage(parseMotdMessage(data))
+		}
+		return nil
 
-		if c.handlers.onConnected != nil {
-			c.handlers.onConnected()
+	case "CAP": // CAP * ACK or CAP * NAK in reply to CAP REQ
+		var capMessage = parseCapMessage(data)
+		if capMessage.Subcommand != "ACK" && capMessage.Subcommand != "NAK" {
+			return errUnsetIRCCommand
+		}
+		var missing = c.updateCapabilities(capMessage)
+		if c.handlers.onCapabilities != nil {
+			c.handlers.onCapabilities(capMessage)
+		}
+		if len(missing) > 0 && c.config.RequireCapabilities {
+			return ErrCapabilitiesDenied
 		}
+		c.rcvdCapReply = true
+		c.checkRegistered()
 		return nil
 
 	case "CLEARCHAT":
+		if source := parseSharedChatSource(data.Tags); len(data.Params) == 1 && (source == nil || !source.OtherChannel) {
+			c.resolveClear(data.Params[0])
+		}
+		if c.handlers.onClearChatMessage == nil && c.history == nil {
+			return nil
+		}
+		var clearChatMessage = parseClearChatMessage(data)
+		if c.dropSharedChat(clearChatMessage.Source) {
+			return nil
+		}
+		if c.history != nil {
+			c.history.clearChat(clearChatMessage)
+		}
 		if c.handlers.onClearChatMessage != nil {
-			c.handlers.onClearChatMessage(parseClearChatMessage(data))
+			c.handlers.onClearChatMessage(clearChatMessage)
 		}
 		return nil
 
 	case "CLEARMSG":
+		if c.handlers.onClearMsgMessage == nil && c.history == nil {
+			return nil
+		}
+		var clearMsgMessage = parseClearMsgMessage(data)
+		if c.dropSharedChat(clearMsgMessage.Source) {
+			return nil
+		}
+		if c.history != nil {
+			c.history.clearMsg(clearMsgMessage)
+		}
 		if c.handlers.onClearMsgMessage != nil {
-			c.handlers.onClearMsgMessage(parseClearMsgMessage(data))
+			c.handlers.onClearMsgMessage(clearMsgMessage)
 		}
 		return nil
 
 	case "GLOBALUSERSTATE":
+		var globalUserstateMessage = parseGlobalUserstateMessage(data)
+		c.updateSelf(globalUserstateMessage)
 		if c.handlers.onGlobalUserstateMessage != nil {
-			c.handlers.onGlobalUserstateMessage(parseGlobalUserstateMessage(data))
+			c.handlers.onGlobalUserstateMessage(globalUserstateMessage)
 		}
 		return nil
 
@@ -68,6 +126,8 @@ func (c *Client) handleIRCData(data IRCData) error {
 
 	case "NOTICE":
 		var noticeMessage, err = parseNoticeMessage(data)
+		c.failJoin(noticeMessage)
+		c.resolveModeration(noticeMessage)
 		if c.handlers.onNoticeMessage != nil {
 			c.handlers.onNoticeMessage(noticeMessage)
 		}
@@ -77,42 +137,74 @@ func (c *Client) handleIRCData(data IRCData) error {
 		if c.handlers.onReconnectMessage != nil {
 			c.handlers.onReconnectMessage(parseReconnectMessage(data))
 		}
+		if c.config.Connection.Reconnect && c.config.Connection.Handover && c.handover != nil {
+			c.handover()
+			return nil
+		}
 		return errReconnect
 
 	case "ROOMSTATE":
+		var roomstateMessage = parseRoomstateMessage(data)
+		c.resolveJoin(roomstateMessage.Channel, ChannelJoined, JoinResult{Channel: roomstateMessage.Channel, Status: JoinJoined})
+		c.updateRoomSettings(roomstateMessage)
 		if c.handlers.onRoomstateMessage != nil {
-			c.handlers.onRoomstateMessage(parseRoomstateMessage(data))
+			c.handlers.onRoomstateMessage(roomstateMessage)
 		}
 		return nil
 
 	case "USERNOTICE":
 		if c.handlers.onUserNoticeMessage != nil {
-			c.handlers.onUserNoticeMessage(parseUsernoticeMessage(data))
+			var usernoticeMessage = parseUsernoticeMessage(data)
+			if !c.dropSharedChat(usernoticeMessage.Source) {
+				c.handlers.onUserNoticeMessage(usernoticeMessage)
+			}
 		}
 		return nil
 
 	case "USERSTATE":
+		var userstateMessage = parseUserstateMessage(data)
+		c.updateSelfIn(userstateMessage)
 		if c.handlers.onUserstateMessage != nil {
-			c.handlers.onUserstateMessage(parseUserstateMessage(data))
+			c.handlers.onUserstateMessage(userstateMessage)
 		}
 		return nil
 
 	case "353": // RPL_NAMREPLY RFC1459 ; aka NAMES on twitch dev docs
 		// WARNING: deprecated, but not removed yet
+		if c.handlers.onNamesMessage == nil && c.presence == nil {
+			return nil
+		}
+		var namesMessage = parseNamesMessage(data)
+		if c.presence != nil {
+			c.presence.addNames(namesMessage)
+		}
 		if c.handlers.onNamesMessage != nil {
-			c.handlers.onNamesMessage(parseNamesMessage(data))
+			c.handlers.onNamesMessage(namesMessage)
 		}
 		return nil
 
 	case "JOIN":
+		var joinMessage = parseJoinMessage(data)
+		c.confirmJoin(joinMessage)
+		if c.presence != nil {
+			c.presence.seen(joinMessage.Channel, joinMessage.Username, time.Now())
+		}
 		if c.handlers.onJoinMessage != nil {
-			c.handlers.onJoinMessage(parseJoinMessage(data))
+			c.handlers.onJoinMessage(joinMessage)
 		}
 		return nil
 
 	case "PART":
+		var partMessage = parsePartMessage(data)
+		if c.presence != nil {
+			if strings.EqualFold(partMessage.Username, c.config.Identity.Username) {
+				c.presence.forget(partMessage.Channel)
+			} else {
+				c.presence.left(partMessage.Channel, partMessage.Username)
+			}
+		}
 		if c.handlers.onPartMessage != nil {
-			c.handlers.onPartMessage(parsePartMessage(data))
+			c.handlers.onPartMessage(partMessage)
 		}
 		return nil
 
@@ -140,8 +232,34 @@ func (c *Client) handleIRCData(data IRCData) error {
 		return nil
 
 	case "PRIVMSG":
+		if source := parseSharedChatSource(data.Tags); c.presence != nil && len(data.Params) > 0 && (source == nil || !source.OtherChannel) {
+			c.presence.seen(data.Params[0], parseUsernameFromPrefix(data.Prefix), time.Now())
+		}
+		if c.handlers.onPrivateMessage == nil && c.handlers.onFirstMessage == nil &&
+			c.handlers.onChannelPointsRedemption == nil && c.handlers.onHighlightedMessage == nil && c.history == nil {
+			return nil
+		}
+		var privateMessage = parsePrivateMessage(data, c.config.CheermotePrefixes)
+		if c.dropSharedChat(privateMessage.Source) {
+			return nil
+		}
+		if c.history != nil {
+			c.history.add(privateMessage)
+		}
+		if c.config.IgnoreSelf && c.isSelf(privateMessage.User) {
+			return nil
+		}
 		if c.handlers.onPrivateMessage != nil {
-			c.handlers.onPrivateMessage(parsePrivateMessage(data))
+			c.handlers.onPrivateMessage(privateMessage)
+		}
+		if privateMessage.FirstMessage && c.handlers.onFirstMessage != nil {
+			c.handlers.onFirstMessage(privateMessage)
+		}
+		if privateMessage.CustomRewardID != "" && c.handlers.onChannelPointsRedemption != nil {
+			c.handlers.onChannelPointsRedemption(privateMessage)
+		}
+		if privateMessage.Highlighted && c.handlers.onHighlightedMessage != nil {
+			c.handlers.onHighlightedMessage(privateMessage)
 		}
 		return nil
 
@@ -151,20 +269,24 @@ func (c *Client) handleIRCData(data IRCData) error {
 		}
 		return nil
 
+	case "366": // RPL_ENDOFNAMES RFC1459 ; end of NAMES
+		var endOfNamesMessage = parseEndOfNamesMessage(data)
+		if c.presence != nil {
+			c.presence.endNames(endOfNamesMessage.Channel, time.Now())
+		}
+		if c.handlers.onEndOfNamesMessage != nil {
+			c.handlers.onEndOfNamesMessage(endOfNamesMessage)
+		}
+		return nil
+
+	case "421": // ERR_UNKNOWNCOMMAND RFC1459 ; invalid IRC Command
+		if c.handlers.onUnknownCommandMessage != nil {
+			c.handlers.onUnknownCommandMessage(parseUnknownCommandMessage(data))
+		}
+		return nil
+
 	// UNIMPLEMENTED
 	// ------------------
-	// tmi.twitch.tv
-	// ------------------
-	// 002  ; RPL_YOURHOST  RFC2812 ; "Your host is tmi.twitch.tv"
-	// 003  ; RPL_CREATED   RFC2812 ; "This server is rather new"
-	// 004  ; RPL_MYINFO    RFC2812 ; "-" ; part of post registration greeting
-	// 375  ; RPL_MOTDSTART RFC1459 ; "-" ;  start message of the day
-	// 372  ; RPL_MOTD      RFC1459 ; "You are in a maze of twisty passages, all alike" ; message of the day
-	// 376  ; RPL_ENDOFMOTD RFC1459 ; "\u003e" which is a > ; end message of the day
-	// CAP  ; CAP * ACK ; acknowledgement of membership
-	// 421  ; ERR_UNKNOWNCOMMAND RFC1459 ; invalid IRC Command
-	//
-	// ------------------
 	// jtv
 	// ------------------
 	// MODE ; deprecated
@@ -172,8 +294,8 @@ func (c *Client) handleIRCData(data IRCData) error {
 	// ------------------
 	// other or no prefix
 	// ------------------
-	// 366 ; RPL_ENDOFNAMES RFC1459 ; end of NAMES
-	case "002", "003", "004", "375", "372", "376", "CAP", "SERVERCHANGE", "421", "MODE", "366":
+	// SERVERCHANGE
+	case "SERVERCHANGE", "MODE":
 		return errUnsetIRCCommand
 
 	// NOT RECOGNIZED
@@ -181,3 +303,8 @@ func (c *Client) handleIRCData(data IRCData) error {
 		return errUnrecognizedIRCCommand
 	}
 }
+
+// dropSharedChat reports whether a message from source should not be passed to the callbacks.
+func (c *Client) dropSharedChat(source *SharedChatSource) bool {
+	return c.config.SharedChat == SharedChatDrop && source != nil && source.OtherChannel
+}
@@ -128,17 +128,19 @@ func TestTmiHandlers(t *testing.T) {
 		{"PONG", nil},
 		{"PRIVMSG", nil},
 		{"WHISPER", nil},
-		{"002", errUnsetIRCCommand},
-		{"003", errUnsetIRCCommand},
-		{"004", errUnsetIRCCommand},
-		{"375", errUnsetIRCCommand},
-		{"372", errUnsetIRCCommand},
-		{"376", errUnsetIRCCommand},
+		{"002", nil},
+		{"003", nil},
+		{"004", nil},
+		{"375", nil},
+		{"372", nil},
+		{"376", nil},
+		{"CAP * ACK :twitch.tv/tags", nil},
+		{"CAP * NAK :twitch.tv/tags", nil},
 		{"CAP", errUnsetIRCCommand},
 		{"SERVERCHANGE", errUnsetIRCCommand},
-		{"421", errUnsetIRCCommand},
+		{"421", nil},
 		{"MODE", errUnsetIRCCommand},
-		{"366", errUnsetIRCCommand},
+		{"366", nil},
 		{"RANDOMCOMMAND", errUnrecognizedIRCCommand},
 	}
 
@@ -157,9 +159,9 @@ func TestTmiHandlers(t *testing.T) {
 
 func TestAllHandlersCallOnMessageWhenSet(t *testing.T) {
 	results := make(map[MessageType]bool)
-	types := []MessageType{UNSET, CLEARCHAT, CLEARMSG, GLOBALUSERSTATE, HOSTTARGET, NOTICE, RECONNECT, ROOMSTATE, USERNOTICE, USERSTATE, NAMES, JOIN, PART, PING, PONG, PRIVMSG, WHISPER}
+	types := []MessageType{UNSET, CLEARCHAT, CLEARMSG, GLOBALUSERSTATE, HOSTTARGET, NOTICE, RECONNECT, ROOMSTATE, USERNOTICE, USERSTATE, NAMES, JOIN, PART, PING, PONG, PRIVMSG, WHISPER, CAP, WELCOME, MOTD, ENDOFNAMES, UNKNOWNCOMMAND}
 
-	var wantUnsetCounter = 12
+	var wantUnsetCounter = 3
 	var unsetCounter int
 	var onConnectedCalled bool
 
@@ -191,7 +193,7 @@ func TestAllHandlersCallOnMessageWhenSet(t *testing.T) {
 		{"375", nil},
 		{"372", nil},
 		{"376", nil},
-		{"CAP", nil},
+		{"CAP * ACK :twitch.tv/tags twitch.tv/commands twitch.tv/membership", nil},
 		{"SERVERCHANGE", nil},
 		{"421", nil},
 		{"MODE", nil},
@@ -203,6 +205,7 @@ func TestAllHandlersCallOnMessageWhenSet(t *testing.T) {
 
 	c.OnUnsetMessage(func(m UnsetMessage) { results[m.Type] = true; unsetCounter++ })
 	c.OnConnected(func() { onConnectedCalled = true })
+	c.OnCapabilities(func(m CapMessage) { results[m.Type] = true })
 	c.OnClearChatMessage(func(m ClearChatMessage) { results[m.Type] = true })
 	c.OnClearMsgMessage(func(m ClearMsgMessage) { results[m.Type] = true })
 	c.OnGlobalUserstateMessage(func(m GlobalUserstateMessage) { results[m.Type] = true })
@@ -219,6 +222,10 @@ func TestAllHandlersCallOnMessageWhenSet(t *testing.T) {
 	c.OnPongMessage(func(m PongMessage) { results[m.Type] = true })
 	c.OnPrivateMessage(func(m PrivateMessage) { results[m.Type] = true })
 	c.OnWhisperMessage(func(m WhisperMessage) { results[m.Type] = true })
+	c.OnWelcomeMessage(func(m WelcomeMessage) { results[m.Type] = true })
+	c.OnMotdMessage(func(m MotdMessage) { results[m.Type] = true })
+	c.OnEndOfNamesMessage(func(m EndOfNamesMessage) { results[m.Type] = true })
+	c.OnUnknownCommandMessage(func(m UnknownCommandMessage) { results[m.Type] = true })
 
 	for _, test := range tests {
 		err := c.handleIRCMessage(test.in)
@@ -241,3 +248,91 @@ func TestAllHandlersCallOnMessageWhenSet(t *testing.T) {
 		t.Errorf("OnConnect handler never called")
 	}
 }
+
+func TestPrivateMessageHooks(t *testing.T) {
+	var first, redemption, highlighted int
+
+	c := NewClient(NewClientConfig("", ""))
+	c.OnFirstMessage(func(m PrivateMessage) { first++ })
+	c.OnChannelPointsRedemption(func(m PrivateMessage) { redemption++ })
+	c.OnHighlightedMessage(func(m PrivateMessage) { highlighted++ })
+
+	for _, raw := range []string{
+		"@first-msg=1 :u!u@u.tmi.twitch.tv PRIVMSG #c :hello",
+		"@custom-reward-id=abc :u!u@u.tmi.twitch.tv PRIVMSG #c :redeemed",
+		"@msg-id=highlighted-message :u!u@u.tmi.twitch.tv PRIVMSG #c :look at me",
+		"@first-msg=0 :u!u@u.tmi.twitch.tv PRIVMSG #c :plain",
+	} {
+		if err := c.handleIRCMessage(raw); err != nil {
+			t.Error(err)
+		}
+	}
+
+	assertIntsEqual(t, "OnFirstMessage calls", first, 1)
+	assertIntsEqual(t, "OnChannelPointsRedemption calls", redemption, 1)
+	assertIntsEqual(t, "OnHighlightedMessage calls", highlighted, 1)
+}
+
+func TestSharedChatDrop(t *testing.T) {
+	var privmsgs, usernotices, clearchats, clearmsgs int
+
+	config := NewClientConfig("", "")
+	config.SharedChat = SharedChatDrop
+	c := NewClient(config)
+	c.OnPrivateMessage(func(m PrivateMessage) { privmsgs++ })
+	c.OnUserNoticeMessage(func(m UsernoticeMessage) { usernotices++ })
+	c.OnClearChatMessage(func(m ClearChatMessage) { clearchats++ })
+	c.OnClearMsgMessage(func(m ClearMsgMessage) { clearmsgs++ })
+
+	for _, raw := range []string{
+		"@room-id=100;source-room-id=100 :u!u@u.tmi.twitch.tv PRIVMSG #c :from here",
+		"@room-id=100;source-room-id=200 :u!u@u.tmi.twitch.tv PRIVMSG #c :from a partner",
+		"@room-id=100 :u!u@u.tmi.twitch.tv PRIVMSG #c :not shared",
+		"@msg-id=sub;room-id=100;source-room-id=200 :tmi.twitch.tv USERNOTICE #c",
+		"@room-id=100;source-room-id=200 :tmi.twitch.tv CLEARCHAT #c :u",
+		"@room-id=100;source-room-id=200 :tmi.twitch.tv CLEARMSG #c :bad",
+	} {
+		if err := c.handleIRCMessage(raw); err != nil {
+			t.Error(err)
+		}
+	}
+
+	assertIntsEqual(t, "PRIVMSG calls", privmsgs, 2)
+	assertIntsEqual(t, "USERNOTICE calls", usernotices, 0)
+	assertIntsEqual(t, "CLEARCHAT calls", clearchats, 0)
+	assertIntsEqual(t, "CLEARMSG calls", clearmsgs, 0)
+}
+
+func TestCapabilityNegotiation(t *testing.T) {
+	var connectedCalls int
+	var capMessages []CapMessage
+
+	c := NewClient(NewClientConfig("", ""))
+	c.OnConnected(func() { connectedCalls++ })
+	c.OnCapabilities(func(m CapMessage) { capMessages = append(capMessages, m) })
+
+	if err := c.handleIRCMessage(":tmi.twitch.tv 001 justinfan123 :Welcome, GLHF!"); err != nil {
+		t.Error(err)
+	}
+	assertIntsEqual(t, "OnConnected calls before CAP reply", connectedCalls, 0)
+
+	if err := c.handleIRCMessage(":tmi.twitch.tv CAP * ACK :twitch.tv/tags twitch.tv/commands"); err != nil {
+		t.Error(err)
+	}
+	assertIntsEqual(t, "OnConnected calls after CAP reply", connectedCalls, 1)
+	assertIntsEqual(t, "OnCapabilities calls", len(capMessages), 1)
+	assertStringsEqual(t, "Subcommand", capMessages[0].Subcommand, "ACK")
+	assertStringSlicesEqual(t, "Capabilities", c.Capabilities(), []string{CapTags, CapCommands})
+
+	config := NewClientConfig("", "")
+	config.RequireCapabilities = true
+	c = NewClient(config)
+	c.OnConnected(func() { connectedCalls++ })
+
+	c.handleIRCMessage(":tmi.twitch.tv 001 justinfan123 :Welcome, GLHF!")
+	if err := c.handleIRCMessage(":tmi.twitch.tv CAP * NAK :twitch.tv/tags twitch.tv/commands twitch.tv/membership"); err != ErrCapabilitiesDenied {
+		t.Errorf("got error: %v, want error: %v", err, ErrCapabilitiesDenied)
+	}
+	assertIntsEqual(t, "OnConnected calls after NAK", connectedCalls, 1)
+	assertIntsEqual(t, "Capabilities after NAK", len(c.Capabilities()), 0)
+}
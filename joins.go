@@ -0,0 +1,235 @@
+package tmi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ChannelStatus for where a channel the client was told to join is at.
+type ChannelStatus int
+
+const (
+	// ChannelPending for a channel whose JOIN has not been confirmed by the server yet
+	ChannelPending ChannelStatus = iota
+	// ChannelJoined for a channel the server confirmed with a JOIN or ROOMSTATE
+	ChannelJoined
+	// ChannelFailed for a channel the server refused, e.g. because it is suspended
+	ChannelFailed
+)
+
+func (cs ChannelStatus) String() string {
+	switch cs {
+	case ChannelPending:
+		return "pending"
+	case ChannelJoined:
+		return "joined"
+	case ChannelFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// JoinStatus for how joining a channel with JoinWait turned out.
+type JoinStatus int
+
+const (
+	// JoinJoined for a channel the server confirmed
+	JoinJoined JoinStatus = iota
+	// JoinSuspended for a channel that is suspended
+	JoinSuspended
+	// JoinBanned for a channel that was closed for Terms of Service violations
+	JoinBanned
+	// JoinTimeout for a channel that was not confirmed or refused before the context was done
+	JoinTimeout
+)
+
+func (js JoinStatus) String() string {
+	switch js {
+	case JoinJoined:
+		return "joined"
+	case JoinSuspended:
+		return "suspended"
+	case JoinBanned:
+		return "banned"
+	case JoinTimeout:
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
+// JoinResult is how joining one channel with JoinWait turned out.
+type JoinResult struct {
+	Channel string     `json:"channel"`
+	Status  JoinStatus `json:"status"`
+	Err     error      `json:"-"` // the *NoticeError for JoinSuspended and JoinBanned, the context's error for JoinTimeout
+}
+
+// channelState is the client's record of a channel it was told to join.
+type channelState struct {
+	status  ChannelStatus
+	sent    bool              // JOIN has been sent on the current connection
+	waiters []chan JoinResult // JoinWait calls waiting on the channel
+	lostAt  time.Time         // when the connection was lost while joined, zero once rejoined
+}
+
+// JoinWait joins channels like Join, and waits until the server confirms or refuses each of them,
+// or until ctx is done. Results are in the same order as channels.
+func (c *Client) JoinWait(ctx context.Context, channels ...string) ([]JoinResult, error) {
+	if len(channels) < 1 {
+		return nil, errors.New("channels was empty or nil")
+	}
+
+	var results = make([]JoinResult, len(channels))
+	var waiters = make([]chan JoinResult, len(channels))
+
+	c.channelsMutex.Lock()
+	for i, channel := range channels {
+		channel = formatChannel(channel)
+		results[i] = JoinResult{Channel: channel, Status: JoinTimeout}
+		if state, ok := c.channels[channel]; ok && state.status == ChannelJoined {
+			results[i].Status = JoinJoined
+			continue
+		}
+		waiters[i] = make(chan JoinResult, 1)
+		var state = c.channels[channel]
+		state.waiters = append(state.waiters, waiters[i])
+		c.channels[channel] = state
+	}
+	c.channelsMutex.Unlock()
+
+	if err := c.Join(channels...); err != nil {
+		return nil, err
+	}
+
+	for i := range channels {
+		if waiters[i] == nil {
+			continue
+		}
+		select {
+		case results[i] = <-waiters[i]:
+		case <-ctx.Done():
+			c.removeJoinWaiter(results[i].Channel, waiters[i])
+			results[i].Err = ctx.Err()
+		}
+	}
+	return results, nil
+}
+
+// Channels returns the status of each channel the client was told to join and has not parted.
+func (c *Client) Channels() map[string]ChannelStatus {
+	var channels = make(map[string]ChannelStatus)
+	c.channelsMutex.Lock()
+	for channel, state := range c.channels {
+		channels[channel] = state.status
+	}
+	c.channelsMutex.Unlock()
+	return channels
+}
+
+// confirms channel as joined from a JOIN for the client's own user.
+func (c *Client) confirmJoin(m JoinMessage) {
+	if !strings.EqualFold(m.Username, c.config.Identity.Username) {
+		return
+	}
+	c.resolveJoin(m.Channel, ChannelJoined, JoinResult{Channel: m.Channel, Status: JoinJoined})
+}
+
+// joinFailures are the NOTICEs that refuse a JOIN, and the status each one fails the channel with.
+// Other failures, such as msg_banned, answer a message sent to the channel rather than the JOIN.
+var joinFailures = map[NoticeMsgID]JoinStatus{
+	MsgIDMsgChannelSuspended: JoinSuspended,
+	MsgIDTOSBan:              JoinBanned,
+}
+
+// fails a pending channel when a NOTICE says it cannot be joined.
+func (c *Client) failJoin(m NoticeMessage) {
+	var status, ok = joinFailures[NoticeMsgID(m.MsgID)]
+	if !ok {
+		return
+	}
+
+	c.channelsMutex.Lock()
+	var state, joining = c.channels[m.Channel]
+	c.channelsMutex.Unlock()
+	if !joining || state.status != ChannelPending {
+		return
+	}
+	c.resolveJoin(m.Channel, ChannelFailed, JoinResult{Channel: m.Channel, Status: status, Err: m.Err()})
+}
+
+// sets a channel's status and hands result to anything waiting on it.
+// Rejoining a channel after the connection was lost calls onGap.
+func (c *Client) resolveJoin(channel string, status ChannelStatus, result JoinResult) {
+	c.channelsMutex.Lock()
+	var state, ok = c.channels[channel]
+	if !ok {
+		c.channelsMutex.Unlock()
+		return
+	}
+	state.status = status
+	for _, waiter := range state.waiters {
+		waiter <- result
+	}
+	state.waiters = nil
+	var lostAt = state.lostAt
+	if status == ChannelJoined {
+		state.lostAt = time.Time{}
+	}
+	c.channels[channel] = state
+	c.channelsMutex.Unlock()
+
+	if status == ChannelJoined && !lostAt.IsZero() && c.handlers.onGap != nil {
+		var now = time.Now()
+		c.handlers.onGap(Gap{
+			Channel:  channel,
+			From:     lostAt,
+			To:       now,
+			Duration: now.Sub(lostAt),
+		})
+	}
+}
+
+// records when the connection was lost for each joined channel, keeping the earliest time over failed reconnects.
+// The channel's presence is forgotten, to be resynced from the NAMES list when it is rejoined.
+func (c *Client) markGaps(lostAt time.Time) {
+	var joined []string
+	c.channelsMutex.Lock()
+	for channel, state := range c.channels {
+		if state.status != ChannelJoined {
+			continue
+		}
+		joined = append(joined, channel)
+		if state.lostAt.IsZero() {
+			state.lostAt = lostAt
+			c.channels[channel] = state
+		}
+	}
+	c.channelsMutex.Unlock()
+
+	if c.presence != nil {
+		for _, channel := range joined {
+			c.presence.forget(channel)
+		}
+	}
+}
+
+func (c *Client) removeJoinWaiter(channel string, waiter chan JoinResult) {
+	c.channelsMutex.Lock()
+	defer c.channelsMutex.Unlock()
+
+	var state, ok = c.channels[channel]
+	if !ok {
+		return
+	}
+	for i := range state.waiters {
+		if state.waiters[i] == waiter {
+			state.waiters = append(state.waiters[:i], state.waiters[i+1:]...)
+			break
+		}
+	}
+	c.channels[channel] = state
+}
@@ -0,0 +1,117 @@
+package tmi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJoinWait(t *testing.T) {
+	c := NewClient(NewClientConfig("me", "oauth:token"))
+
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond*200)
+	defer cancel()
+
+	var done = make(chan []JoinResult)
+	go func() {
+		results, err := c.JoinWait(ctx, "Joined", "suspended", "banned", "timeout")
+		if err != nil {
+			t.Error(err)
+		}
+		done <- results
+	}()
+
+	for len(c.Channels()) < 4 {
+		time.Sleep(time.Millisecond)
+	}
+	for _, raw := range []string{
+		":someone!someone@someone.tmi.twitch.tv JOIN #joined",
+		":me!me@me.tmi.twitch.tv JOIN #joined",
+		"@msg-id=msg_channel_suspended :tmi.twitch.tv NOTICE #suspended :This channel has been suspended.",
+		"@msg-id=tos_ban :tmi.twitch.tv NOTICE #banned :The community has closed channel banned due to Terms of Service violations.",
+		"@msg-id=msg_banned :tmi.twitch.tv NOTICE #timeout :You are permanently banned from talking in timeout.",
+	} {
+		if err := c.handleIRCMessage(raw); err != nil {
+			t.Error(err)
+		}
+	}
+
+	results := <-done
+	want := []JoinResult{
+		{Channel: "#joined", Status: JoinJoined},
+		{Channel: "#suspended", Status: JoinSuspended, Err: ErrNoticeChannelSuspended},
+		{Channel: "#banned", Status: JoinBanned, Err: ErrNoticeChannelSuspended},
+		{Channel: "#timeout", Status: JoinTimeout, Err: context.DeadlineExceeded},
+	}
+	assertIntsEqual(t, "len(results)", len(results), len(want))
+	for i := range results {
+		assertStringsEqual(t, "Channel", results[i].Channel, want[i].Channel)
+		if results[i].Status != want[i].Status {
+			t.Errorf("%v Status: got %v, want %v", results[i].Channel, results[i].Status, want[i].Status)
+		}
+		if !errors.Is(results[i].Err, want[i].Err) || (results[i].Err == nil) != (want[i].Err == nil) {
+			t.Errorf("%v Err: got %v, want %v", results[i].Channel, results[i].Err, want[i].Err)
+		}
+	}
+
+	channels := c.Channels()
+	wantStatus := map[string]ChannelStatus{
+		"#joined":    ChannelJoined,
+		"#suspended": ChannelFailed,
+		"#banned":    ChannelFailed,
+		"#timeout":   ChannelPending,
+	}
+	for channel, status := range wantStatus {
+		if channels[channel] != status {
+			t.Errorf("Channels()[%v]: got %v, want %v", channel, channels[channel], status)
+		}
+	}
+}
+
+func TestJoinWaitAlreadyJoined(t *testing.T) {
+	c := NewClient(NewClientConfig("me", "oauth:token"))
+	c.Join("#c")
+	c.handleIRCMessage("@emote-only=0;room-id=1 :tmi.twitch.tv ROOMSTATE #c")
+
+	results, err := c.JoinWait(context.Background(), "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Status != JoinJoined {
+		t.Errorf("Status: got %v, want %v", results[0].Status, JoinJoined)
+	}
+
+	if _, err := c.JoinWait(context.Background()); err == nil {
+		t.Errorf("JoinWait without channels should return an error")
+	}
+}
+
+func TestGap(t *testing.T) {
+	c := NewClient(NewClientConfig("me", "oauth:token"))
+	var gaps []Gap
+	c.OnGap(func(g Gap) { gaps = append(gaps, g) })
+
+	c.Join("a", "b")
+	c.handleIRCMessage("@emote-only=0;room-id=1 :tmi.twitch.tv ROOMSTATE #a")
+	assertIntsEqual(t, "gaps after first join", len(gaps), 0)
+
+	var lostAt = time.Now().Add(-time.Minute)
+	c.markGaps(lostAt)
+	c.markGaps(time.Now()) // a failed reconnect keeps the first time
+	c.onConnectedJoins()
+
+	c.handleIRCMessage("@emote-only=0;room-id=1 :tmi.twitch.tv ROOMSTATE #a")
+	c.handleIRCMessage("@emote-only=0;room-id=2 :tmi.twitch.tv ROOMSTATE #b")
+	c.handleIRCMessage("@emote-only=0;room-id=1 :tmi.twitch.tv ROOMSTATE #a")
+
+	assertIntsEqual(t, "gaps", len(gaps), 1) // #b was never joined before the connection was lost
+	assertStringsEqual(t, "Channel", gaps[0].Channel, "#a")
+	if !gaps[0].From.Equal(lostAt) {
+		t.Errorf("From: got %v, want %v", gaps[0].From, lostAt)
+	}
+	assertDurationsEqual(t, "Duration", gaps[0].Duration, gaps[0].To.Sub(gaps[0].From))
+	if gaps[0].Duration < time.Minute {
+		t.Errorf("Duration: got %v, want at least %v", gaps[0].Duration, time.Minute)
+	}
+}
@@ -42,6 +42,16 @@ const (
 	PRIVMSG
 	// WHISPER for WHISPER message type
 	WHISPER
+	// CAP for CAP message type
+	CAP
+	// WELCOME for 001, 002, 003, and 004 message types
+	WELCOME
+	// MOTD for 375, 372, and 376 message types
+	MOTD
+	// ENDOFNAMES for 366 message type
+	ENDOFNAMES
+	// UNKNOWNCOMMAND for 421 message type
+	UNKNOWNCOMMAND
 )
 
 func (mt MessageType) String() string {
@@ -65,6 +75,11 @@ func (mt MessageType) String() string {
 		"PONG",
 		"PRIVMSG",
 		"WHISPER",
+		"CAP",
+		"WELCOME",
+		"MOTD",
+		"ENDOFNAMES",
+		"UNKNOWNCOMMAND",
 	}[mt]
 }
 
@@ -96,8 +111,9 @@ type ClearChatMessage struct {
 	Text    string      `json:"text"` // a sentence explaining what the clear chat did
 	Type    MessageType `json:"type"`
 
-	BanDuration time.Duration `json:"ban-duration"` // duration of ban, omitted if permanent
-	Target      string        `json:"target"`       // target of the ban, omitted if not a timeout or ban
+	BanDuration time.Duration     `json:"ban-duration"` // duration of ban, omitted if permanent
+	Source      *SharedChatSource `json:"source"`       // set if the channel is in a shared chat session
+	Target      string            `json:"target"`       // target of the ban, omitted if not a timeout or ban
 }
 
 // ClearMsgMessage data received on singular message deletion.
@@ -108,8 +124,9 @@ type ClearMsgMessage struct {
 	Text    string      `json:"text"` // the deleted message
 	Type    MessageType `json:"type"`
 
-	Login       string `json:"login"`         // name of user who sent deleted message
-	TargetMsgID string `json:"target-msg-id"` // msg id of the deleted message
+	Login       string            `json:"login"`         // name of user who sent deleted message
+	Source      *SharedChatSource `json:"source"`        // set if the channel is in a shared chat session
+	TargetMsgID string            `json:"target-msg-id"` // msg id of the deleted message
 }
 
 // GlobalUserstateMessage data about user that successfully logged in.
@@ -142,11 +159,14 @@ type NoticeMessage struct {
 	Text    string      `json:"text"`
 	Type    MessageType `json:"type"`
 
-	Enabled bool     `json:"enabled"` // set when Notice is one of: emoteonly, uniquechat, subonly
-	Mods    []string `json:"mods"`    // list of mods for Channel when Notice is set to mods
-	MsgID   string   `json:"msg-id"`  // msg-id value from Data.Tags, or parse-error / login-error if the key doesn't exist
-	Notice  string   `json:"notice"`  // Notice is one of: automod, emoteonly, mods, uniquechat, subonly, vips, notice (notice is the default)
-	VIPs    []string `json:"vips"`    // list of vips for Channel when Notice is set to vips
+	Category NoticeCategory `json:"category"` // category of MsgID, NoticeUnknown if MsgID isn't in the catalogue
+	Duration time.Duration  `json:"duration"` // set when MsgID is a timeout, slow mode, or followers-only notice that includes a duration
+	Enabled  bool           `json:"enabled"`  // set when Notice is one of: emoteonly, uniquechat, subonly, or MsgID is a followers/slow on/off notice
+	Mods     []string       `json:"mods"`     // list of mods for Channel when Notice is set to mods
+	MsgID    string         `json:"msg-id"`   // msg-id value from Data.Tags, or parse_error / login_failure if the key doesn't exist
+	Notice   string         `json:"notice"`   // Notice is one of: automod, emoteonly, mods, uniquechat, subonly, vips, notice (notice is the default)
+	Target   string         `json:"target"`   // user targeted by the command the notice responds to (ban_success, mod_success, etc.)
+	VIPs     []string       `json:"vips"`     // list of vips for Channel when Notice is set to vips
 }
 
 // ReconnectMessage data when the server requests that clients reconnect.
@@ -163,6 +183,7 @@ type RoomstateMessage struct {
 	IRCType string      `json:"irc-type"`
 	Type    MessageType `json:"type"`
 
+	RoomID string `json:"room-id"` // id of the channel's owner
 	// emote-only, followers-only, r9k(uniquechat), rituals, slow(slowmode), subs-only
 	States map[string]RoomState `json:"states"` // the states in the roomstate tags
 }
@@ -173,6 +194,32 @@ type RoomState struct { // note followers-only: -1 (disabled), 0 (enabled immedi
 	Delay   time.Duration `json:"delay"`   // seconds between messages (slow), minutes post-follow (followers-only)
 }
 
+// RoomSettings for a channel's chat settings, merged from full and partial ROOMSTATE messages.
+type RoomSettings struct {
+	EmoteOnly         bool          `json:"emote-only"`
+	FollowersOnly     bool          `json:"followers-only"`
+	FollowersDuration time.Duration `json:"followers-duration"` // how long a user must follow before chatting, 0 for immediately
+	R9K               bool          `json:"r9k"`
+	Slow              time.Duration `json:"slow"` // wait between a user's messages, 0 when slow mode is off
+	SubsOnly          bool          `json:"subs-only"`
+	RoomID            string        `json:"room-id"`
+}
+
+// RoomSettingsChange when a ROOMSTATE message changes a joined channel's RoomSettings.
+type RoomSettingsChange struct {
+	Channel string       `json:"channel"`
+	Before  RoomSettings `json:"before"` // zero value on the first ROOMSTATE after joining
+	After   RoomSettings `json:"after"`
+}
+
+// Gap when a channel is rejoined after the connection was lost, for the time its messages were not received.
+type Gap struct {
+	Channel  string        `json:"channel"`
+	From     time.Time     `json:"from"` // when the connection was lost
+	To       time.Time     `json:"to"`   // when the channel was rejoined
+	Duration time.Duration `json:"duration"`
+}
+
 // UsernoticeMessage data when a user subscribes to a channel, incoming raid, and channel rituals.
 type UsernoticeMessage struct {
 	Channel string      `json:"channel"`
@@ -181,12 +228,13 @@ type UsernoticeMessage struct {
 	Text    string      `json:"text"`
 	Type    MessageType `json:"type"`
 
-	Emotes    []Emote `json:"emotes"`     // parsed emotes string
-	ID        string  `json:"id"`         // message id
-	MsgID     string  `json:"msg-id"`     // not a unique message id, but the type of notice
-	MsgParams IRCTags `json:"msg-params"` // any msg-param tags for the notice
-	SystemMsg string  `json:"system-msg"` // message printed in chat on the notice
-	User      *User   `json:"user"`       // user who caused the notice
+	Emotes    []Emote           `json:"emotes"`     // parsed emotes string
+	ID        string            `json:"id"`         // message id
+	MsgID     string            `json:"msg-id"`     // not a unique message id, but the type of notice
+	MsgParams IRCTags           `json:"msg-params"` // any msg-param tags for the notice
+	Source    *SharedChatSource `json:"source"`     // set if the channel is in a shared chat session
+	SystemMsg string            `json:"system-msg"` // message printed in chat on the notice
+	User      *User             `json:"user"`       // user who caused the notice
 }
 
 // UserstateMessage data when a user joins a channel or sends a PrivateMessage.
@@ -210,6 +258,15 @@ type NamesMessage struct { // WARNING: deprecated, but not removed yet
 	Users []string `json:"users"` // list of usernames
 }
 
+// EndOfNamesMessage data when the server has finished sending the NamesMessages for a channel.
+type EndOfNamesMessage struct {
+	Channel string      `json:"channel"`
+	Data    IRCData     `json:"data"`
+	IRCType string      `json:"irc-type"`
+	Text    string      `json:"text"` // "End of /NAMES list"
+	Type    MessageType `json:"type"`
+}
+
 // JoinMessage data when user joins a channel, gives the channel joined and username joined as.
 type JoinMessage struct {
 	Channel string      `json:"channel"`
@@ -254,12 +311,43 @@ type PrivateMessage struct {
 	Text    string      `json:"text"`
 	Type    MessageType `json:"type"`
 
-	Action bool    `json:"action"` // indicates if the /me command was used
-	Bits   int     `json:"bits"`   // number of bits if bits message
-	Emotes []Emote `json:"emotes"` // parsed emotes string
-	ID     string  `json:"id"`     // message id
-	Reply  bool    `json:"reply"`  // indicates if the message is a reply
-	User   *User   `json:"user"`   // user that sent the message
+	Action           bool              `json:"action"`            // indicates if the /me command was used
+	Bits             int               `json:"bits"`              // number of bits if bits message
+	Cheermotes       []Cheermote       `json:"cheermotes"`        // cheermotes in the text if bits message
+	ClientNonce      string            `json:"client-nonce"`      // nonce set by the sender's client
+	CustomRewardID   string            `json:"custom-reward-id"`  // channel points reward id if sent by redeeming a reward
+	Emotes           []Emote           `json:"emotes"`            // parsed emotes string
+	FirstMessage     bool              `json:"first-msg"`         // indicates if it is the user's first message in the channel
+	Flags            []AutoModFlag     `json:"flags"`             // parsed AutoMod flags tag
+	Highlighted      bool              `json:"highlighted"`       // indicates if the message was highlighted with channel points
+	HypeChat         *HypeChat         `json:"hype-chat"`         // set if the message is a paid Hype Chat
+	ID               string            `json:"id"`                // message id
+	MsgID            string            `json:"msg-id"`            // msg-id tag, e.g. highlighted-message
+	Reply            bool              `json:"reply"`             // indicates if the message is a reply
+	ReturningChatter bool              `json:"returning-chatter"` // indicates if the user is a returning chatter
+	RoomID           string            `json:"room-id"`           // channel's user id
+	SentTime         time.Time         `json:"tmi-sent-ts"`       // when the server received the message
+	SkipSubsMode     bool              `json:"skip-subs-mode"`    // indicates if channel points were used to send the message in subs-only mode
+	Source           *SharedChatSource `json:"source"`            // set if the channel is in a shared chat session
+	User             *User             `json:"user"`              // user that sent the message
+}
+
+// HypeChat is the paid amount of a Hype Chat (pinned chat) message.
+type HypeChat struct {
+	Amount          int    `json:"amount"`            // amount paid in the currency's minor unit, see Exponent
+	Currency        string `json:"currency"`          // ISO 4217 currency code
+	Exponent        int    `json:"exponent"`          // number of decimal places in Amount
+	Level           string `json:"level"`             // ONE through TEN
+	IsSystemMessage bool   `json:"is-system-message"` // indicates if Twitch sent the message for the user
+}
+
+// SharedChatSource is where a message in a shared chat session was originally sent, from the source tags.
+type SharedChatSource struct {
+	Badges       []Badge `json:"badges"`        // the user's badges in the source channel
+	BadgeInfos   []Badge `json:"badge-infos"`   // the user's badge-info in the source channel
+	ID           string  `json:"id"`            // message id in the source channel
+	OtherChannel bool    `json:"other-channel"` // indicates if the message was sent in another channel than the one it was received in
+	RoomID       string  `json:"room-id"`       // source channel's user id
 }
 
 // ReplyParentMsg is the information provided in tags when a PrivateMessage is a reply.
@@ -284,10 +372,51 @@ type WhisperMessage struct {
 	User   *User   `json:"user"`   // message sender
 }
 
-// Badge represents a user chat badge badge/1
+// CapMessage data when the server replies to the client's capability request.
+type CapMessage struct {
+	Data    IRCData     `json:"data"`
+	IRCType string      `json:"irc-type"`
+	Type    MessageType `json:"type"`
+
+	Capabilities []string `json:"capabilities"` // capabilities the reply is for
+	Subcommand   string   `json:"subcommand"`   // ACK if the capabilities were granted, NAK if they were denied
+}
+
+// WelcomeMessage data for each of the 001, 002, 003, and 004 replies sent after logging in.
+type WelcomeMessage struct {
+	Data    IRCData     `json:"data"`
+	IRCType string      `json:"irc-type"` // 001, 002, 003, or 004
+	Text    string      `json:"text"`
+	Type    MessageType `json:"type"`
+
+	Nick string `json:"nick"` // the nick the server assigned to the client
+}
+
+// MotdMessage data for each line of the message of the day sent after logging in.
+type MotdMessage struct {
+	Data    IRCData     `json:"data"`
+	IRCType string      `json:"irc-type"` // 375 for the start, 372 for a line, 376 for the end
+	Text    string      `json:"text"`
+	Type    MessageType `json:"type"`
+
+	Nick string `json:"nick"` // the nick the server assigned to the client
+}
+
+// UnknownCommandMessage data when the server did not recognize a command the client sent.
+type UnknownCommandMessage struct {
+	Data    IRCData     `json:"data"`
+	IRCType string      `json:"irc-type"`
+	Text    string      `json:"text"` // "Unknown command"
+	Type    MessageType `json:"type"`
+
+	Command string `json:"command"` // the command that was not recognized
+}
+
+// Badge represents a user chat badge badge/1, or a badge-info entry subscriber/24
 type Badge struct {
-	Name  string `json:"name"`
-	Value int    `json:"value"`
+	Name    string `json:"name"`
+	Value   int    `json:"value"`   // Version as an int, 0 if Version is not a number
+	Version string `json:"version"` // raw version, e.g. 1, blue-1, or a prediction outcome for badge-info
 }
 
 // Emote information provided in tags.
@@ -306,6 +435,7 @@ type EmotePosition struct {
 // User info provided in tags.
 type User struct {
 	BadgeInfo   string  `json:"badge-info"`
+	BadgeInfos  []Badge `json:"badge-infos"` // parsed BadgeInfo: exact subscriber/founder months, prediction outcome
 	Badges      []Badge `json:"badges"`
 	Broadcaster bool    `json:"broadcaster"`
 	Color       string  `json:"color"`
@@ -0,0 +1,301 @@
+package tmi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ModerationResult is the NOTICE the server answered a moderation command with.
+// MsgID and Notice are empty when the command was sent by a Moderator other than the default, e.g. a HelixModerator,
+// and when a clear was answered by the channel's CLEARCHAT.
+type ModerationResult struct {
+	Channel  string        `json:"channel"`
+	Target   string        `json:"target"`   // user the command acted on, empty for commands without one
+	MsgID    NoticeMsgID   `json:"msg-id"`   // msg-id of the answer, empty if no answer was received
+	Duration time.Duration `json:"duration"` // duration from the answer for timeouts, slow mode, and followers-only mode
+	Notice   NoticeMessage `json:"notice"`   // the answer
+}
+
+// moderationWaiter is a moderation command waiting for its answer.
+type moderationWaiter struct {
+	channel string
+	target  string // lowercase login, empty when the answer is not about a user
+	ids     map[NoticeMsgID]bool
+	answer  chan NoticeMessage
+}
+
+// moderationFailures are answers any moderation command can get.
+var moderationFailures = []NoticeMsgID{
+	MsgIDInvalidUser,
+	MsgIDMsgBanned,
+	MsgIDMsgChannelSuspended,
+	MsgIDMsgRatelimit,
+	MsgIDNoPermission,
+	MsgIDUnavailableCommand,
+	MsgIDUnrecognizedCmd,
+}
+
+// clearChatAnswer marks the commands answered by a CLEARCHAT for their whole channel, which is how the server confirms a clear.
+const clearChatAnswer NoticeMsgID = "CLEARCHAT"
+
+// Marker and Raid have no Wait variants: the server does not answer them with a NOTICE when they succeed.
+var (
+	banAnswers = []NoticeMsgID{
+		MsgIDBanSuccess, MsgIDAlreadyBanned, MsgIDBadBanAdmin, MsgIDBadBanAnon,
+		MsgIDBadBanBroadcaster, MsgIDBadBanMod, MsgIDBadBanSelf, MsgIDBadBanStaff, MsgIDUsageBan,
+	}
+	unbanAnswers   = []NoticeMsgID{MsgIDUnbanSuccess, MsgIDBadUnbanNoBan, MsgIDUsageUnban}
+	timeoutAnswers = []NoticeMsgID{
+		MsgIDTimeoutSuccess, MsgIDBadTimeoutAdmin, MsgIDBadTimeoutAnon, MsgIDBadTimeoutBroadcaster,
+		MsgIDBadTimeoutDuration, MsgIDBadTimeoutMod, MsgIDBadTimeoutSelf, MsgIDBadTimeoutStaff, MsgIDUsageTimeout,
+	}
+	untimeoutAnswers = []NoticeMsgID{MsgIDUntimeoutSuccess, MsgIDUntimeoutBanned, MsgIDTimeoutNoTimeout, MsgIDUsageUntimeout}
+	modAnswers       = []NoticeMsgID{MsgIDModSuccess, MsgIDBadModBanned, MsgIDBadModMod, MsgIDUsageMod}
+	unmodAnswers     = []NoticeMsgID{MsgIDUnmodSuccess, MsgIDBadUnmodMod, MsgIDUsageUnmod}
+	vipAnswers       = []NoticeMsgID{
+		MsgIDVIPSuccess, MsgIDBadVIPAchievementIncomplete, MsgIDBadVIPGranteeAlreadyVIP,
+		MsgIDBadVIPGranteeBanned, MsgIDBadVIPMaxVIPsReached, MsgIDUsageVIP,
+	}
+	unvipAnswers        = []NoticeMsgID{MsgIDUnVIPSuccess, MsgIDBadUnVIPGranteeNotVIP, MsgIDUsageUnVIP}
+	deleteAnswers       = []NoticeMsgID{MsgIDDeleteMessageSuccess, MsgIDDeleteStaffMessageSuccess, MsgIDBadDeleteMessageBroadcaster, MsgIDBadDeleteMessageMod, MsgIDUsageDelete}
+	commercialAnswers   = []NoticeMsgID{MsgIDCommercialSuccess, MsgIDBadCommercialError, MsgIDUsageCommercial}
+	slowAnswers         = []NoticeMsgID{MsgIDSlowOn, MsgIDAlreadySlowOn, MsgIDBadSlowDuration, MsgIDUsageSlowOn}
+	slowOffAnswers      = []NoticeMsgID{MsgIDSlowOff, MsgIDAlreadySlowOff, MsgIDUsageSlowOff}
+	followersAnswers    = []NoticeMsgID{MsgIDFollowersOn, MsgIDFollowersOnZero, MsgIDAlreadyFollowersOn, MsgIDUsageFollowersOn}
+	followersOffAnswers = []NoticeMsgID{MsgIDFollowersOff, MsgIDAlreadyFollowersOff, MsgIDUsageFollowersOff}
+	emoteOnlyAnswers    = []NoticeMsgID{MsgIDEmoteOnlyOn, MsgIDAlreadyEmoteOnlyOn, MsgIDUsageEmoteOnlyOn}
+	emoteOnlyOffAnswers = []NoticeMsgID{MsgIDEmoteOnlyOff, MsgIDAlreadyEmoteOnlyOff, MsgIDUsageEmoteOnlyOff}
+	subsAnswers         = []NoticeMsgID{MsgIDSubsOn, MsgIDAlreadySubsOn, MsgIDUsageSubsOn}
+	subsOffAnswers      = []NoticeMsgID{MsgIDSubsOff, MsgIDAlreadySubsOff, MsgIDUsageSubsOff}
+	r9kAnswers          = []NoticeMsgID{MsgIDR9kOn, MsgIDAlreadyR9kOn, MsgIDUsageR9kOn}
+	r9kOffAnswers       = []NoticeMsgID{MsgIDR9kOff, MsgIDAlreadyR9kOff, MsgIDUsageR9kOff}
+	clearAnswers        = []NoticeMsgID{clearChatAnswer, MsgIDUsageClear}
+	colorAnswers        = []NoticeMsgID{MsgIDColorChanged, MsgIDTurboOnlyColor, MsgIDUsageColor, MsgIDUsageUserColor}
+	unraidAnswers       = []NoticeMsgID{MsgIDUnraidSuccess, MsgIDUnraidErrorNoActiveRaid, MsgIDUnraidErrorUnexpected, MsgIDUsageUnraid}
+)
+
+// BanWait bans user like Ban, and waits for the server's answer until ctx is done.
+func (c *Client) BanWait(ctx context.Context, channel, user, reason string) (ModerationResult, error) {
+	if len(reason)+len(user) > 490 {
+		return ModerationResult{}, errors.New("user + reason must be shorter than 490 characters")
+	}
+	return c.moderate(ctx, channel, user, banAnswers, func() error { return c.moderator.Ban(channel, user, reason) })
+}
+
+// UnbanWait unbans user like Unban, and waits for the server's answer until ctx is done.
+func (c *Client) UnbanWait(ctx context.Context, channel, user string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, user, unbanAnswers, func() error { return c.moderator.Unban(channel, user) })
+}
+
+// TimeoutWait times out user like Timeout, and waits for the server's answer until ctx is done.
+func (c *Client) TimeoutWait(ctx context.Context, channel, user, seconds string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, user, timeoutAnswers, func() error { return c.moderator.Timeout(channel, user, seconds) })
+}
+
+// UntimeoutWait removes a timeout like Untimeout, and waits for the server's answer until ctx is done.
+func (c *Client) UntimeoutWait(ctx context.Context, channel, user string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, user, untimeoutAnswers, func() error { return c.moderator.Untimeout(channel, user) })
+}
+
+// ModWait makes user a moderator like Mod, and waits for the server's answer until ctx is done.
+func (c *Client) ModWait(ctx context.Context, channel, user string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, user, modAnswers, func() error { return c.moderator.Mod(channel, user) })
+}
+
+// UnmodWait removes user as a moderator like Unmod, and waits for the server's answer until ctx is done.
+func (c *Client) UnmodWait(ctx context.Context, channel, user string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, user, unmodAnswers, func() error { return c.moderator.Unmod(channel, user) })
+}
+
+// VIPWait makes user a vip like VIP, and waits for the server's answer until ctx is done.
+func (c *Client) VIPWait(ctx context.Context, channel, user string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, user, vipAnswers, func() error { return c.moderator.VIP(channel, user) })
+}
+
+// UnVIPWait removes user as a vip like UnVIP, and waits for the server's answer until ctx is done.
+func (c *Client) UnVIPWait(ctx context.Context, channel, user string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, user, unvipAnswers, func() error { return c.moderator.UnVIP(channel, user) })
+}
+
+// DeleteWait deletes a message like Delete, and waits for the server's answer until ctx is done.
+func (c *Client) DeleteWait(ctx context.Context, channel, messageID string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", deleteAnswers, func() error { return c.moderator.Delete(channel, messageID) })
+}
+
+// CommercialWait starts a commercial break like Commercial, and waits for the server's answer until ctx is done.
+func (c *Client) CommercialWait(ctx context.Context, channel, seconds string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", commercialAnswers, func() error { return c.moderator.Commercial(channel, seconds) })
+}
+
+// SlowWait turns on slow mode like Slow, and waits for the server's answer until ctx is done.
+func (c *Client) SlowWait(ctx context.Context, channel, seconds string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", slowAnswers, func() error { return c.moderator.Slow(channel, seconds) })
+}
+
+// SlowOffWait turns off slow mode like SlowOff, and waits for the server's answer until ctx is done.
+func (c *Client) SlowOffWait(ctx context.Context, channel string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", slowOffAnswers, func() error { return c.moderator.SlowOff(channel) })
+}
+
+// FollowersWait turns on followersonly mode like Followers, and waits for the server's answer until ctx is done.
+func (c *Client) FollowersWait(ctx context.Context, channel, duration string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", followersAnswers, func() error { return c.moderator.Followers(channel, duration) })
+}
+
+// FollowersOffWait turns off followersonly mode like FollowersOff, and waits for the server's answer until ctx is done.
+func (c *Client) FollowersOffWait(ctx context.Context, channel string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", followersOffAnswers, func() error { return c.moderator.FollowersOff(channel) })
+}
+
+// EmoteOnlyWait turns on emoteonly mode like EmoteOnly, and waits for the server's answer until ctx is done.
+func (c *Client) EmoteOnlyWait(ctx context.Context, channel string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", emoteOnlyAnswers, func() error { return c.moderator.EmoteOnly(channel) })
+}
+
+// EmoteOnlyOffWait turns off emoteonly mode like EmoteOnlyOff, and waits for the server's answer until ctx is done.
+func (c *Client) EmoteOnlyOffWait(ctx context.Context, channel string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", emoteOnlyOffAnswers, func() error { return c.moderator.EmoteOnlyOff(channel) })
+}
+
+// SubscribersWait turns on subscribers only mode like Subscribers, and waits for the server's answer until ctx is done.
+func (c *Client) SubscribersWait(ctx context.Context, channel string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", subsAnswers, func() error { return c.moderator.Subscribers(channel) })
+}
+
+// SubscribersOffWait turns off subscribers only mode like SubscribersOff, and waits for the server's answer until ctx is done.
+func (c *Client) SubscribersOffWait(ctx context.Context, channel string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", subsOffAnswers, func() error { return c.moderator.SubscribersOff(channel) })
+}
+
+// R9kBetaWait turns on r9kbeta(uniquechat) mode like R9kBeta, and waits for the server's answer until ctx is done.
+func (c *Client) R9kBetaWait(ctx context.Context, channel string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", r9kAnswers, func() error { return c.moderator.R9kBeta(channel) })
+}
+
+// R9kBetaOffWait turns off r9kbeta(uniquechat) mode like R9kBetaOff, and waits for the server's answer until ctx is done.
+func (c *Client) R9kBetaOffWait(ctx context.Context, channel string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", r9kOffAnswers, func() error { return c.moderator.R9kBetaOff(channel) })
+}
+
+// ClearWait clears the chat like Clear, and waits for the server's answer until ctx is done.
+// A successful clear is answered by the channel's CLEARCHAT rather than a NOTICE.
+func (c *Client) ClearWait(ctx context.Context, channel string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", clearAnswers, func() error { return c.moderator.Clear(channel) })
+}
+
+// ColorWait changes the client user's name color like Color, and waits for the server's answer until ctx is done.
+// The answer is received in the client user's own channel.
+func (c *Client) ColorWait(ctx context.Context, color string) (ModerationResult, error) {
+	return c.moderate(ctx, c.config.Identity.Username, "", colorAnswers, func() error { return c.moderator.Color(color) })
+}
+
+// UnraidWait cancels an active raid like Unraid, and waits for the server's answer until ctx is done.
+func (c *Client) UnraidWait(ctx context.Context, channel string) (ModerationResult, error) {
+	return c.moderate(ctx, channel, "", unraidAnswers, func() error { return c.moderator.Unraid(channel) })
+}
+
+// moderate sends a moderation command with send, and waits until a NOTICE in channel with one of the command's
+// answers or a failure any moderation command can get is received, or until ctx is done.
+// An answer that names a user only counts when it names target.
+// The error is the answer's *NoticeError when it reports a failure, or the context's error if no answer was received.
+// A Moderator other than the default answers by returning from send, so its error is returned without waiting.
+// moderate charges the moderator rate limiter without waiting.
+func (c *Client) moderate(ctx context.Context, channel, target string, answers []NoticeMsgID, send func() error) (ModerationResult, error) {
+	var result = newModerationResult(channel, target)
+	if err := c.checkModerator(result.Channel); err != nil {
+		return result, err
+	}
+	c.rLimiterMod.take()
+	return c.awaitModeration(ctx, result, answers, send)
+}
+
+// newModerationResult returns the result of a command in channel for target, before it is answered.
+func newModerationResult(channel, target string) ModerationResult {
+	return ModerationResult{
+		Channel: formatChannel(channel),
+		Target:  strings.ToLower(strings.TrimPrefix(target, "@")),
+	}
+}
+
+// awaitModeration sends a command with send and waits for its answer like moderate, without checks or rate limiting.
+func (c *Client) awaitModeration(ctx context.Context, result ModerationResult, answers []NoticeMsgID, send func() error) (ModerationResult, error) {
+	var waiter = &moderationWaiter{
+		channel: result.Channel,
+		target:  result.Target,
+		ids:     make(map[NoticeMsgID]bool, len(answers)+len(moderationFailures)),
+		answer:  make(chan NoticeMessage, 1),
+	}
+	for _, id := range answers {
+		waiter.ids[id] = true
+	}
+	for _, id := range moderationFailures {
+		waiter.ids[id] = true
+	}
+
+	c.moderationsMutex.Lock()
+	c.moderations = append(c.moderations, waiter)
+	c.moderationsMutex.Unlock()
+
+	if err := send(); err != nil || !c.answersByNotice() {
+		c.removeModerationWaiter(waiter)
+		return result, err
+	}
+
+	select {
+	case m := <-waiter.answer:
+		result.MsgID = NoticeMsgID(m.MsgID)
+		result.Duration = m.Duration
+		result.Notice = m
+		return result, m.Err()
+	case <-ctx.Done():
+		c.removeModerationWaiter(waiter)
+		return result, ctx.Err()
+	}
+}
+
+// hands a NOTICE to the oldest moderation command waiting on it, if any.
+func (c *Client) resolveModeration(m NoticeMessage) {
+	c.moderationsMutex.Lock()
+	defer c.moderationsMutex.Unlock()
+
+	for i, waiter := range c.moderations {
+		if waiter.channel != m.Channel || !waiter.ids[NoticeMsgID(m.MsgID)] {
+			continue
+		}
+		if m.Target != "" && waiter.target != "" && m.Target != waiter.target {
+			continue
+		}
+		waiter.answer <- m
+		c.moderations = append(c.moderations[:i], c.moderations[i+1:]...)
+		return
+	}
+}
+
+// hands a CLEARCHAT for the whole channel to the oldest clear waiting in channel, if any.
+func (c *Client) resolveClear(channel string) {
+	c.moderationsMutex.Lock()
+	defer c.moderationsMutex.Unlock()
+
+	for i, waiter := range c.moderations {
+		if waiter.channel != channel || !waiter.ids[clearChatAnswer] {
+			continue
+		}
+		waiter.answer <- NoticeMessage{Channel: channel, Category: NoticeCommandSuccess}
+		c.moderations = append(c.moderations[:i], c.moderations[i+1:]...)
+		return
+	}
+}
+
+func (c *Client) removeModerationWaiter(waiter *moderationWaiter) {
+	c.moderationsMutex.Lock()
+	defer c.moderationsMutex.Unlock()
+
+	for i := range c.moderations {
+		if c.moderations[i] == waiter {
+			c.moderations = append(c.moderations[:i], c.moderations[i+1:]...)
+			return
+		}
+	}
+}
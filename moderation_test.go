@@ -0,0 +1,100 @@
+package tmi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestModerationWait(t *testing.T) {
+	c := NewClient(NewClientConfig("me", "oauth:token"))
+
+	type answer struct {
+		result ModerationResult
+		err    error
+	}
+	wait := func(f func(ctx context.Context) (ModerationResult, error), notices ...string) answer {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		answers := make(chan answer, 1)
+		go func() {
+			result, err := f(ctx)
+			answers <- answer{result, err}
+		}()
+		<-c.outbound // the waiter is registered before the command is sent
+		for _, notice := range notices {
+			c.handleIRCMessage(notice)
+		}
+		return <-answers
+	}
+
+	got := wait(func(ctx context.Context) (ModerationResult, error) { return c.BanWait(ctx, "#c", "Bob", "") },
+		"@msg-id=ban_success :tmi.twitch.tv NOTICE #c :alice is now banned from this channel.",
+		"@msg-id=ban_success :tmi.twitch.tv NOTICE #c :bob is now banned from this channel.")
+	if got.err != nil {
+		t.Errorf("BanWait: got error %v, want nil", got.err)
+	}
+	assertStringsEqual(t, "BanWait Target", got.result.Target, "bob")
+	assertStringsEqual(t, "BanWait MsgID", string(got.result.MsgID), string(MsgIDBanSuccess))
+
+	got = wait(func(ctx context.Context) (ModerationResult, error) { return c.TimeoutWait(ctx, "c", "bob", "600") },
+		"@msg-id=timeout_success :tmi.twitch.tv NOTICE #c :bob has been timed out for 600 seconds.")
+	if got.err != nil {
+		t.Errorf("TimeoutWait: got error %v, want nil", got.err)
+	}
+	assertDurationsEqual(t, "TimeoutWait Duration", got.result.Duration, 600*time.Second)
+
+	got = wait(func(ctx context.Context) (ModerationResult, error) { return c.UnbanWait(ctx, "c", "bob") },
+		"@msg-id=bad_unban_no_ban :tmi.twitch.tv NOTICE #c :bob is not banned from this channel.")
+	var noticeErr *NoticeError
+	if !errors.As(got.err, &noticeErr) || !errors.Is(got.err, ErrNoticeCommandError) {
+		t.Errorf("UnbanWait: got error %v, want a command error", got.err)
+	}
+	assertStringsEqual(t, "UnbanWait MsgID", string(got.result.MsgID), string(MsgIDBadUnbanNoBan))
+
+	got = wait(func(ctx context.Context) (ModerationResult, error) { return c.SlowWait(ctx, "c", "30") },
+		"@msg-id=no_permission :tmi.twitch.tv NOTICE #other :You don't have permission to perform that action.",
+		"@msg-id=no_permission :tmi.twitch.tv NOTICE #c :You don't have permission to perform that action.")
+	if !errors.Is(got.err, ErrNoticePermissionDenied) {
+		t.Errorf("SlowWait: got error %v, want %v", got.err, ErrNoticePermissionDenied)
+	}
+
+	got = wait(func(ctx context.Context) (ModerationResult, error) { return c.ClearWait(ctx, "c") },
+		"@room-id=1;tmi-sent-ts=1 :tmi.twitch.tv CLEARCHAT #c :bob",
+		"@room-id=1;source-room-id=2;tmi-sent-ts=1 :tmi.twitch.tv CLEARCHAT #c",
+		"@msg-id=usage_clear :tmi.twitch.tv NOTICE #other :Usage: \"/clear\"",
+		"@room-id=1;tmi-sent-ts=1 :tmi.twitch.tv CLEARCHAT #c")
+	if got.err != nil {
+		t.Errorf("ClearWait: got error %v, want nil", got.err)
+	}
+	assertStringsEqual(t, "ClearWait MsgID", string(got.result.MsgID), "")
+
+	got = wait(func(ctx context.Context) (ModerationResult, error) { return c.ColorWait(ctx, "blue") },
+		"@msg-id=color_changed :tmi.twitch.tv NOTICE #me :Your color has been changed.")
+	if got.err != nil {
+		t.Errorf("ColorWait: got error %v, want nil", got.err)
+	}
+	assertStringsEqual(t, "ColorWait MsgID", string(got.result.MsgID), string(MsgIDColorChanged))
+
+	got = wait(func(ctx context.Context) (ModerationResult, error) { return c.UnraidWait(ctx, "c") },
+		"@msg-id=unraid_error_no_active_raid :tmi.twitch.tv NOTICE #c :You do not have an active raid.")
+	if !errors.Is(got.err, ErrNoticeCommandError) {
+		t.Errorf("UnraidWait: got error %v, want %v", got.err, ErrNoticeCommandError)
+	}
+	assertStringsEqual(t, "UnraidWait MsgID", string(got.result.MsgID), string(MsgIDUnraidErrorNoActiveRaid))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.ModWait(ctx, "c", "bob"); err != context.DeadlineExceeded {
+		t.Errorf("ModWait: got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	<-c.outbound
+	assertIntsEqual(t, "waiters after timeout", len(c.moderations), 0)
+
+	c.handleIRCMessage("@badge-info=;badges=;color=;display-name=Me;emote-sets=0;mod=0;subscriber=0;user-type= :tmi.twitch.tv USERSTATE #plain")
+	if _, err := c.VIPWait(context.Background(), "plain", "bob"); err != ErrNotModerator {
+		t.Errorf("VIPWait in #plain: got error %v, want %v", err, ErrNotModerator)
+	}
+}
@@ -0,0 +1,490 @@
+package tmi
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Moderator sends the moderation and chat settings commands of a Client.
+// The client validates arguments before calling it, and channel is as passed to the client's method.
+type Moderator interface {
+	Ban(channel, user, reason string) error
+	Unban(channel, user string) error
+	Clear(channel string) error
+	Color(color string) error
+	Commercial(channel, seconds string) error
+	Delete(channel, messageID string) error
+	EmoteOnly(channel string) error
+	EmoteOnlyOff(channel string) error
+	Followers(channel, duration string) error
+	FollowersOff(channel string) error
+	Marker(channel, description string) error
+	Mod(channel, user string) error
+	Unmod(channel, user string) error
+	R9kBeta(channel string) error
+	R9kBetaOff(channel string) error
+	Raid(channel, target string) error
+	Unraid(channel string) error
+	Slow(channel, seconds string) error
+	SlowOff(channel string) error
+	Subscribers(channel string) error
+	SubscribersOff(channel string) error
+	Timeout(channel, user, seconds string) error
+	Untimeout(channel, user string) error
+	VIP(channel, user string) error
+	UnVIP(channel, user string) error
+}
+
+// SetModerator sets the Moderator the client's moderation and chat settings commands are sent with.
+// A nil m sets the default, which sends them as chat commands over IRC, and sets the moderator rate limit back to RLimMsgMod.
+// SetModerator should be called before Connect.
+func (c *Client) SetModerator(m Moderator) {
+	if m == nil {
+		m = ircModerator{c}
+		c.rLimiterMod = NewRateLimiter(RLimMsgMod)
+	}
+	c.moderator = m
+}
+
+// modCommand sends a moderator-only command with command, unless the client is known not to be a moderator in channel.
+// It charges the moderator rate limiter without waiting, so bulk moderation paces itself around it.
+func (c *Client) modCommand(channel string, command func() error) error {
+	if err := c.checkModerator(channel); err != nil {
+		return err
+	}
+	c.rLimiterMod.take()
+	return command()
+}
+
+// reportModeratorErr passes an error from a command that does not return one to onModeratorError.
+func (c *Client) reportModeratorErr(err error) {
+	if err != nil && c.handlers.onModeratorError != nil {
+		c.handlers.onModeratorError(err)
+	}
+}
+
+// answersByNotice reports whether the server answers the client's moderation commands with a NOTICE.
+func (c *Client) answersByNotice() bool {
+	var _, ok = c.moderator.(ircModerator)
+	return ok
+}
+
+// ircModerator sends commands as chat commands in PRIVMSG messages, and the server answers with a NOTICE.
+type ircModerator struct {
+	c *Client
+}
+
+func (m ircModerator) Ban(channel, user, reason string) error {
+	if reason != "" {
+		m.c.Say(channel, "/ban "+user+" "+reason)
+		return nil
+	}
+	m.c.Say(channel, "/ban "+user)
+	return nil
+}
+
+func (m ircModerator) Unban(channel, user string) error {
+	m.c.Say(channel, "/unban "+user)
+	return nil
+}
+
+func (m ircModerator) Clear(channel string) error {
+	m.c.Say(channel, "/clear")
+	return nil
+}
+
+func (m ircModerator) Color(color string) error {
+	m.c.Say("#"+m.c.config.Identity.Username, "/color "+color)
+	return nil
+}
+
+func (m ircModerator) Commercial(channel, seconds string) error {
+	m.c.Say(channel, "/commercial "+seconds)
+	return nil
+}
+
+func (m ircModerator) Delete(channel, messageID string) error {
+	m.c.Say(channel, "/delete "+messageID)
+	return nil
+}
+
+func (m ircModerator) EmoteOnly(channel string) error {
+	m.c.Say(channel, "/emoteonly")
+	return nil
+}
+
+func (m ircModerator) EmoteOnlyOff(channel string) error {
+	m.c.Say(channel, "/emoteonlyoff")
+	return nil
+}
+
+func (m ircModerator) Followers(channel, duration string) error {
+	m.c.Say(channel, "/followers "+duration)
+	return nil
+}
+
+func (m ircModerator) FollowersOff(channel string) error {
+	m.c.Say(channel, "/followersoff")
+	return nil
+}
+
+func (m ircModerator) Marker(channel, description string) error {
+	if description != "" {
+		m.c.Say(channel, "/marker "+description)
+		return nil
+	}
+	m.c.Say(channel, "/marker")
+	return nil
+}
+
+func (m ircModerator) Mod(channel, user string) error {
+	m.c.Say(channel, "/mod "+user)
+	return nil
+}
+
+func (m ircModerator) Unmod(channel, user string) error {
+	m.c.Say(channel, "/unmod "+user)
+	return nil
+}
+
+func (m ircModerator) R9kBeta(channel string) error {
+	m.c.Say(channel, "/r9kbeta")
+	return nil
+}
+
+func (m ircModerator) R9kBetaOff(channel string) error {
+	m.c.Say(channel, "/r9kbetaoff")
+	return nil
+}
+
+func (m ircModerator) Raid(channel, target string) error {
+	m.c.Say(channel, "/raid "+target)
+	return nil
+}
+
+func (m ircModerator) Unraid(channel string) error {
+	m.c.Say(channel, "/unraid")
+	return nil
+}
+
+func (m ircModerator) Slow(channel, seconds string) error {
+	m.c.Say(channel, "/slow "+seconds)
+	return nil
+}
+
+func (m ircModerator) SlowOff(channel string) error {
+	m.c.Say(channel, "/slowoff")
+	return nil
+}
+
+func (m ircModerator) Subscribers(channel string) error {
+	m.c.Say(channel, "/subscribers")
+	return nil
+}
+
+func (m ircModerator) SubscribersOff(channel string) error {
+	m.c.Say(channel, "/subscribersoff")
+	return nil
+}
+
+func (m ircModerator) Timeout(channel, user, seconds string) error {
+	if seconds != "" {
+		m.c.Say(channel, "/timeout "+user+" "+seconds)
+		return nil
+	}
+	m.c.Say(channel, "/timeout "+user)
+	return nil
+}
+
+func (m ircModerator) Untimeout(channel, user string) error {
+	m.c.Say(channel, "/untimeout "+user)
+	return nil
+}
+
+func (m ircModerator) VIP(channel, user string) error {
+	m.c.Say(channel, "/vip "+user)
+	return nil
+}
+
+func (m ircModerator) UnVIP(channel, user string) error {
+	m.c.Say(channel, "/unvip "+user)
+	return nil
+}
+
+// HelixModerator sends commands to the Helix API instead of as chat commands.
+// The token must have the scopes of the commands used, e.g. moderator:manage:banned_users for Ban.
+type HelixModerator struct {
+	helix *helixClient
+}
+
+// UseHelix sets a HelixModerator as the client's Moderator, and returns it. UseHelix should be called before Connect.
+func (c *Client) UseHelix(config HelixConfig) *HelixModerator {
+	var m = &HelixModerator{helix: newHelixClient(config, c.config.Identity)}
+	c.moderator = m
+	c.rLimiterMod = NewRateLimiter(RLimHelixDefault)
+	return m
+}
+
+// defaultTimeout is how long Timeout times out a user when no seconds are given, like the chat command.
+const defaultTimeout = time.Minute * 10
+
+// Ban bans user from channel with the Ban User endpoint.
+func (m *HelixModerator) Ban(channel, user, reason string) error {
+	return m.ban(channel, user, 0, reason)
+}
+
+// Unban unbans user from channel with the Unban User endpoint.
+func (m *HelixModerator) Unban(channel, user string) error {
+	var broadcasterID, moderatorID, err = m.helix.channelIDs(channel)
+	if err != nil {
+		return err
+	}
+	userIDs, err := m.helix.userIDs(user)
+	if err != nil {
+		return err
+	}
+	return m.helix.do(http.MethodDelete, "/moderation/bans", url.Values{
+		"broadcaster_id": {broadcasterID},
+		"moderator_id":   {moderatorID},
+		"user_id":        {userIDs[0]},
+	}, nil, nil)
+}
+
+// Clear clears all chat messages in channel with the Delete Chat Messages endpoint.
+func (m *HelixModerator) Clear(channel string) error {
+	return m.Delete(channel, "")
+}
+
+// Color changes the color of the client's user with the Update User Chat Color endpoint.
+func (m *HelixModerator) Color(color string) error {
+	var userIDs, err = m.helix.userIDs(m.helix.login)
+	if err != nil {
+		return err
+	}
+	return m.helix.do(http.MethodPut, "/chat/color", url.Values{"user_id": {userIDs[0]}, "color": {color}}, nil, nil)
+}
+
+// Commercial starts a commercial break in channel with the Start Commercial endpoint.
+func (m *HelixModerator) Commercial(channel, seconds string) error {
+	var length, err = strconv.Atoi(seconds)
+	if err != nil {
+		return errors.New("invalid commercial length: " + seconds)
+	}
+	broadcasterIDs, err := m.helix.userIDs(channel)
+	if err != nil {
+		return err
+	}
+	return m.helix.do(http.MethodPost, "/channels/commercial", nil, map[string]interface{}{
+		"broadcaster_id": broadcasterIDs[0],
+		"length":         length,
+	}, nil)
+}
+
+// Delete deletes the message with messageID in channel with the Delete Chat Messages endpoint.
+func (m *HelixModerator) Delete(channel, messageID string) error {
+	var broadcasterID, moderatorID, err = m.helix.channelIDs(channel)
+	if err != nil {
+		return err
+	}
+	var query = url.Values{"broadcaster_id": {broadcasterID}, "moderator_id": {moderatorID}}
+	if messageID != "" {
+		query.Set("message_id", messageID)
+	}
+	return m.helix.do(http.MethodDelete, "/moderation/chat", query, nil, nil)
+}
+
+// EmoteOnly turns on emoteonly mode in channel with the Update Chat Settings endpoint.
+func (m *HelixModerator) EmoteOnly(channel string) error {
+	return m.chatSettings(channel, map[string]interface{}{"emote_mode": true})
+}
+
+// EmoteOnlyOff turns off emoteonly mode in channel with the Update Chat Settings endpoint.
+func (m *HelixModerator) EmoteOnlyOff(channel string) error {
+	return m.chatSettings(channel, map[string]interface{}{"emote_mode": false})
+}
+
+// Followers turns on followersonly mode in channel with the Update Chat Settings endpoint.
+// A bare number in duration is minutes.
+func (m *HelixModerator) Followers(channel, duration string) error {
+	var d, err = parseCommandDuration(duration, time.Minute)
+	if err != nil {
+		return err
+	}
+	return m.chatSettings(channel, map[string]interface{}{
+		"follower_mode":          true,
+		"follower_mode_duration": int(d / time.Minute),
+	})
+}
+
+// FollowersOff turns off followersonly mode in channel with the Update Chat Settings endpoint.
+func (m *HelixModerator) FollowersOff(channel string) error {
+	return m.chatSettings(channel, map[string]interface{}{"follower_mode": false})
+}
+
+// Marker adds a stream marker in channel with the Create Stream Marker endpoint.
+func (m *HelixModerator) Marker(channel, description string) error {
+	var broadcasterIDs, err = m.helix.userIDs(channel)
+	if err != nil {
+		return err
+	}
+	var body = map[string]interface{}{"user_id": broadcasterIDs[0]}
+	if description != "" {
+		body["description"] = description
+	}
+	return m.helix.do(http.MethodPost, "/streams/markers", nil, body, nil)
+}
+
+// Mod makes user a moderator in channel with the Add Channel Moderator endpoint.
+func (m *HelixModerator) Mod(channel, user string) error {
+	return m.channelRole(http.MethodPost, "/moderation/moderators", channel, user)
+}
+
+// Unmod makes user no longer a moderator in channel with the Remove Channel Moderator endpoint.
+func (m *HelixModerator) Unmod(channel, user string) error {
+	return m.channelRole(http.MethodDelete, "/moderation/moderators", channel, user)
+}
+
+// R9kBeta turns on uniquechat mode in channel with the Update Chat Settings endpoint.
+func (m *HelixModerator) R9kBeta(channel string) error {
+	return m.chatSettings(channel, map[string]interface{}{"unique_chat_mode": true})
+}
+
+// R9kBetaOff turns off uniquechat mode in channel with the Update Chat Settings endpoint.
+func (m *HelixModerator) R9kBetaOff(channel string) error {
+	return m.chatSettings(channel, map[string]interface{}{"unique_chat_mode": false})
+}
+
+// Raid starts a raid on channel to target with the Start a raid endpoint.
+func (m *HelixModerator) Raid(channel, target string) error {
+	var ids, err = m.helix.userIDs(channel, target)
+	if err != nil {
+		return err
+	}
+	return m.helix.do(http.MethodPost, "/raids", url.Values{"from_broadcaster_id": {ids[0]}, "to_broadcaster_id": {ids[1]}}, nil, nil)
+}
+
+// Unraid cancels a raid on channel with the Cancel a raid endpoint.
+func (m *HelixModerator) Unraid(channel string) error {
+	var broadcasterIDs, err = m.helix.userIDs(channel)
+	if err != nil {
+		return err
+	}
+	return m.helix.do(http.MethodDelete, "/raids", url.Values{"broadcaster_id": {broadcasterIDs[0]}}, nil, nil)
+}
+
+// Slow turns on slow mode in channel with the Update Chat Settings endpoint.
+func (m *HelixModerator) Slow(channel, seconds string) error {
+	var d, err = parseCommandDuration(seconds, time.Second)
+	if err != nil {
+		return err
+	}
+	return m.chatSettings(channel, map[string]interface{}{
+		"slow_mode":           true,
+		"slow_mode_wait_time": int(d / time.Second),
+	})
+}
+
+// SlowOff turns off slow mode in channel with the Update Chat Settings endpoint.
+func (m *HelixModerator) SlowOff(channel string) error {
+	return m.chatSettings(channel, map[string]interface{}{"slow_mode": false})
+}
+
+// Subscribers turns on subscribers only mode in channel with the Update Chat Settings endpoint.
+func (m *HelixModerator) Subscribers(channel string) error {
+	return m.chatSettings(channel, map[string]interface{}{"subscriber_mode": true})
+}
+
+// SubscribersOff turns off subscribers only mode in channel with the Update Chat Settings endpoint.
+func (m *HelixModerator) SubscribersOff(channel string) error {
+	return m.chatSettings(channel, map[string]interface{}{"subscriber_mode": false})
+}
+
+// Timeout times out user in channel with the Ban User endpoint, for 10 minutes when seconds is empty.
+func (m *HelixModerator) Timeout(channel, user, seconds string) error {
+	var d = defaultTimeout
+	if seconds != "" {
+		var err error
+		if d, err = parseCommandDuration(seconds, time.Second); err != nil {
+			return err
+		}
+	}
+	return m.ban(channel, user, d, "")
+}
+
+// Untimeout removes a timeout for user in channel with the Unban User endpoint.
+func (m *HelixModerator) Untimeout(channel, user string) error {
+	return m.Unban(channel, user)
+}
+
+// VIP makes user a vip in channel with the Add Channel VIP endpoint.
+func (m *HelixModerator) VIP(channel, user string) error {
+	return m.channelRole(http.MethodPost, "/channels/vips", channel, user)
+}
+
+// UnVIP makes user no longer a vip in channel with the Remove Channel VIP endpoint.
+func (m *HelixModerator) UnVIP(channel, user string) error {
+	return m.channelRole(http.MethodDelete, "/channels/vips", channel, user)
+}
+
+// ban bans user from channel, or times them out when d is more than 0.
+func (m *HelixModerator) ban(channel, user string, d time.Duration, reason string) error {
+	var broadcasterID, moderatorID, err = m.helix.channelIDs(channel)
+	if err != nil {
+		return err
+	}
+	userIDs, err := m.helix.userIDs(user)
+	if err != nil {
+		return err
+	}
+	var data = map[string]interface{}{"user_id": userIDs[0]}
+	if d > 0 {
+		data["duration"] = int(d / time.Second)
+	}
+	if reason != "" {
+		data["reason"] = reason
+	}
+	return m.helix.do(http.MethodPost, "/moderation/bans", url.Values{
+		"broadcaster_id": {broadcasterID},
+		"moderator_id":   {moderatorID},
+	}, map[string]interface{}{"data": data}, nil)
+}
+
+// channelRole adds or removes user's moderator or vip role in channel.
+func (m *HelixModerator) channelRole(method, path, channel, user string) error {
+	var ids, err = m.helix.userIDs(channel, user)
+	if err != nil {
+		return err
+	}
+	return m.helix.do(method, path, url.Values{"broadcaster_id": {ids[0]}, "user_id": {ids[1]}}, nil, nil)
+}
+
+// chatSettings updates the chat settings of channel that are in settings.
+func (m *HelixModerator) chatSettings(channel string, settings map[string]interface{}) error {
+	var broadcasterID, moderatorID, err = m.helix.channelIDs(channel)
+	if err != nil {
+		return err
+	}
+	return m.helix.do(http.MethodPatch, "/chat/settings", url.Values{
+		"broadcaster_id": {broadcasterID},
+		"moderator_id":   {moderatorID},
+	}, settings, nil)
+}
+
+// parseCommandDuration parses a duration argument of a chat command, e.g. "30", "10m", or "1 week". unit is used for bare numbers.
+func parseCommandDuration(s string, unit time.Duration) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if d := parseNoticeDuration(s, unit); d > 0 {
+		return d, nil
+	}
+	return 0, errors.New("invalid duration: " + s)
+}
@@ -0,0 +1,152 @@
+package tmi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeHelix answers /users from its users and records every other request.
+// A chat message with the text dup is dropped as a duplicate.
+type fakeHelix struct {
+	mutex    sync.Mutex
+	requests []string // method, path and query, and body of each request
+	lookups  int
+	users    map[string]string // login to user ID
+	fail     map[string]int    // path to status code to answer with
+}
+
+func newFakeHelix(t *testing.T) (*fakeHelix, *httptest.Server) {
+	f := &fakeHelix{users: map[string]string{"me": "1", "chan": "2", "bob": "3"}, fail: make(map[string]int)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("Client-Id") != "client" {
+			t.Errorf("%v: wrong auth headers %v", r.URL.Path, r.Header)
+		}
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+
+		if r.URL.Path == "/users" {
+			f.lookups++
+			type user struct {
+				ID    string `json:"id"`
+				Login string `json:"login"`
+			}
+			var data []user
+			for _, login := range r.URL.Query()["login"] {
+				if id, ok := f.users[login]; ok {
+					data = append(data, user{id, login})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+" "+strings.TrimSpace(string(body)))
+		if status, ok := f.fail[r.URL.Path]; ok {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":"Forbidden","status":403,"message":"missing scope"}`))
+			return
+		}
+		if r.URL.Path == "/chat/messages" {
+			var resp = `{"data":[{"message_id":"m1","is_sent":true}]}`
+			if strings.Contains(string(body), `"message":"dup"`) {
+				resp = `{"data":[{"message_id":"","is_sent":false,"drop_reason":{"code":"msg_duplicate","message":"Your message is identical to the one you sent within the last 30 seconds."}}]}`
+			}
+			w.Write([]byte(resp))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	return f, srv
+}
+
+func (f *fakeHelix) last() string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if len(f.requests) == 0 {
+		return ""
+	}
+	return f.requests[len(f.requests)-1]
+}
+
+func TestHelixModerator(t *testing.T) {
+	f, srv := newFakeHelix(t)
+	defer srv.Close()
+
+	c := NewClient(NewClientConfig("me", "oauth:token"))
+	c.UseHelix(HelixConfig{BaseURL: srv.URL + "/", ClientID: "client"})
+	var errs []error
+	c.OnModeratorError(func(err error) { errs = append(errs, err) })
+
+	tests := []struct {
+		send func() error
+		want string
+	}{
+		{func() error { return c.Ban("#chan", "Bob", "spam") },
+			`POST /moderation/bans?broadcaster_id=2&moderator_id=1 {"data":{"reason":"spam","user_id":"3"}}`},
+		{func() error { return c.TimeoutFor("chan", "bob", time.Minute) },
+			`POST /moderation/bans?broadcaster_id=2&moderator_id=1 {"data":{"duration":60,"user_id":"3"}}`},
+		{func() error { c.Timeout("chan", "bob", ""); return nil },
+			`POST /moderation/bans?broadcaster_id=2&moderator_id=1 {"data":{"duration":600,"user_id":"3"}}`},
+		{func() error { c.Unban("chan", "bob"); return nil },
+			`DELETE /moderation/bans?broadcaster_id=2&moderator_id=1&user_id=3 `},
+		{func() error { c.Delete("chan", "abc-123"); return nil },
+			`DELETE /moderation/chat?broadcaster_id=2&message_id=abc-123&moderator_id=1 `},
+		{func() error { c.Mod("chan", "bob"); return nil },
+			`POST /moderation/moderators?broadcaster_id=2&user_id=3 `},
+		{func() error { c.Followers("chan", "1h"); return nil },
+			`PATCH /chat/settings?broadcaster_id=2&moderator_id=1 {"follower_mode":true,"follower_mode_duration":60}`},
+		{func() error { c.SlowOff("chan"); return nil },
+			`PATCH /chat/settings?broadcaster_id=2&moderator_id=1 {"slow_mode":false}`},
+		{func() error { return c.CommercialFor("chan", Commercial60s) },
+			`POST /channels/commercial? {"broadcaster_id":"2","length":60}`},
+		{func() error { c.Color("#AABBCC"); return nil },
+			`PUT /chat/color?color=%23AABBCC&user_id=1 `},
+	}
+	for _, test := range tests {
+		if err := test.send(); err != nil {
+			t.Errorf("%v: %v", test.want, err)
+		}
+		assertStringsEqual(t, "request", f.last(), test.want)
+	}
+	assertIntsEqual(t, "user lookups", f.lookups, 2)
+	assertIntsEqual(t, "errors", len(errs), 0)
+	assertIntsEqual(t, "outbound", len(c.outbound), 0)
+
+	f.mutex.Lock()
+	f.fail["/channels/vips"] = http.StatusForbidden
+	f.mutex.Unlock()
+	c.VIP("chan", "bob")
+	c.Unban("chan", "ghost")
+	assertIntsEqual(t, "errors", len(errs), 2)
+	var helixErr *HelixError
+	if len(errs) == 2 && (!errors.As(errs[0], &helixErr) || helixErr.Status != http.StatusForbidden || helixErr.Message != "missing scope") {
+		t.Errorf("VIP: got error %v, want a 403 HelixError", errs[0])
+	}
+
+	result, err := c.BanWait(context.Background(), "chan", "bob", "")
+	if err != nil {
+		t.Errorf("BanWait: got error %v, want nil", err)
+	}
+	assertStringsEqual(t, "BanWait Target", result.Target, "bob")
+
+	f.mutex.Lock()
+	f.fail["/moderation/bans"] = http.StatusForbidden
+	f.mutex.Unlock()
+	if _, err := c.BanWait(context.Background(), "chan", "bob", ""); !errors.As(err, &helixErr) {
+		t.Errorf("BanWait: got error %v, want a HelixError", err)
+	}
+	assertIntsEqual(t, "errors after BanWait", len(errs), 2)
+
+	c.SetModerator(nil)
+	c.Unban("chan", "bob")
+	assertStringsEqual(t, "IRC moderator", <-c.outbound, "PRIVMSG #chan :/unban bob")
+}
@@ -0,0 +1,243 @@
+package tmi
+
+//go:generate go run gen_noticeids.go
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NoticeMsgID is the msg-id tag of a NOTICE message. See noticeids.go for all known values.
+type NoticeMsgID string
+
+// Category returns the NoticeCategory of the msg-id, or NoticeUnknown if it is not a known msg-id.
+func (id NoticeMsgID) Category() NoticeCategory {
+	if category, ok := noticeCategories[id]; ok {
+		return category
+	}
+	return NoticeUnknown
+}
+
+// NoticeCategory groups NOTICE msg-ids by the kind of response they are.
+type NoticeCategory int
+
+const (
+	// NoticeUnknown for msg-ids that are not in the catalogue
+	NoticeUnknown NoticeCategory = iota - 1
+	// NoticeInfo for informational notices that aren't a response to a command
+	NoticeInfo
+	// NoticeCommandSuccess for notices confirming a command or chat setting change
+	NoticeCommandSuccess
+	// NoticeCommandError for notices rejecting a command or message as invalid
+	NoticeCommandError
+	// NoticePermissionDenied for notices rejecting a command or login because of missing permissions
+	NoticePermissionDenied
+	// NoticeRateLimited for notices rejecting a command or message for being sent too quickly
+	NoticeRateLimited
+	// NoticeChannelSuspended for notices about a suspended or closed channel
+	NoticeChannelSuspended
+	// NoticeBanned for notices about the logged in user being banned, timed out or suspended
+	NoticeBanned
+)
+
+func (nc NoticeCategory) String() string {
+	switch nc {
+	case NoticeInfo:
+		return "info"
+	case NoticeCommandSuccess:
+		return "command success"
+	case NoticeCommandError:
+		return "command error"
+	case NoticePermissionDenied:
+		return "permission denied"
+	case NoticeRateLimited:
+		return "rate limited"
+	case NoticeChannelSuspended:
+		return "channel suspended"
+	case NoticeBanned:
+		return "banned"
+	default:
+		return "unknown"
+	}
+}
+
+// IsError reports whether notices of this category mean a command or message failed.
+func (nc NoticeCategory) IsError() bool {
+	return nc > NoticeCommandSuccess
+}
+
+var (
+	// ErrNoticeCommandError matches a NoticeError with category NoticeCommandError using errors.Is.
+	ErrNoticeCommandError = errors.New("command error")
+	// ErrNoticePermissionDenied matches a NoticeError with category NoticePermissionDenied using errors.Is.
+	ErrNoticePermissionDenied = errors.New("permission denied")
+	// ErrNoticeRateLimited matches a NoticeError with category NoticeRateLimited using errors.Is.
+	ErrNoticeRateLimited = errors.New("rate limited")
+	// ErrNoticeChannelSuspended matches a NoticeError with category NoticeChannelSuspended using errors.Is.
+	ErrNoticeChannelSuspended = errors.New("channel suspended")
+	// ErrNoticeBanned matches a NoticeError with category NoticeBanned using errors.Is.
+	ErrNoticeBanned = errors.New("banned")
+)
+
+var noticeCategoryErrs = map[NoticeCategory]error{
+	NoticeCommandError:     ErrNoticeCommandError,
+	NoticePermissionDenied: ErrNoticePermissionDenied,
+	NoticeRateLimited:      ErrNoticeRateLimited,
+	NoticeChannelSuspended: ErrNoticeChannelSuspended,
+	NoticeBanned:           ErrNoticeBanned,
+}
+
+// NoticeError is the error form of a NOTICE message that reports a failure.
+// It matches the ErrNotice* value of its category with errors.Is.
+type NoticeError struct {
+	Category NoticeCategory
+	Channel  string
+	MsgID    NoticeMsgID
+	Text     string
+}
+
+func (e *NoticeError) Error() string {
+	if e.Text != "" {
+		return string(e.MsgID) + ": " + e.Text
+	}
+	return string(e.MsgID)
+}
+
+// Is reports whether target is the ErrNotice* value for the error's category.
+// A login failure also matches ErrLoginFailure.
+func (e *NoticeError) Is(target error) bool {
+	if target == ErrLoginFailure {
+		return e.MsgID == MsgIDLoginFailure
+	}
+	return target != nil && noticeCategoryErrs[e.Category] == target
+}
+
+// Err returns a *NoticeError if the notice reports a failure, otherwise nil.
+func (m NoticeMessage) Err() error {
+	if !m.Category.IsError() {
+		return nil
+	}
+	return &NoticeError{
+		Category: m.Category,
+		Channel:  m.Channel,
+		MsgID:    NoticeMsgID(m.MsgID),
+		Text:     m.Text,
+	}
+}
+
+// noticePayload describes where the target and duration can be found in a notice's text.
+// {} in template marks a capture, target and duration are indexes into the captures.
+type noticePayload struct {
+	template string
+	target   int
+	duration int
+	unit     time.Duration
+}
+
+var noticePayloads = map[NoticeMsgID]noticePayload{
+	MsgIDAlreadyBanned:           {"{} is already banned in this channel.", 0, -1, 0},
+	MsgIDBadBanMod:               {"You cannot ban moderator {} unless you are the owner of this channel.", 0, -1, 0},
+	MsgIDBadModBanned:            {"{} is banned from this channel.", 0, -1, 0},
+	MsgIDBadModMod:               {"{} is already a moderator of this channel.", 0, -1, 0},
+	MsgIDBadTimeoutMod:           {"You cannot timeout moderator {} unless you are the owner of this channel.", 0, -1, 0},
+	MsgIDBadUnbanNoBan:           {"{} is not banned from this channel.", 0, -1, 0},
+	MsgIDBadUnmodMod:             {"{} is not a moderator of this channel.", 0, -1, 0},
+	MsgIDBadUnVIPGranteeNotVIP:   {"{} is not a VIP of this channel.", 0, -1, 0},
+	MsgIDBadVIPGranteeAlreadyVIP: {"{} is already a VIP of this channel.", 0, -1, 0},
+	MsgIDBadVIPGranteeBanned:     {"{} is banned in this channel.", 0, -1, 0},
+	MsgIDBanSuccess:              {"{} is now banned from this channel.", 0, -1, 0},
+	MsgIDFollowersOn:             {"This room is now in {} followers-only mode.", -1, 0, time.Minute},
+	MsgIDHostOn:                  {"Now hosting {}.", 0, -1, 0},
+	MsgIDInvalidUser:             {"Invalid username: {}", 0, -1, 0},
+	MsgIDModSuccess:              {"You have added {} as a moderator of this channel.", 0, -1, 0},
+	MsgIDMsgSlowmode:             {"You will be able to talk again in {} seconds.", -1, 0, time.Second},
+	MsgIDMsgTimedout:             {"You are timed out for {} more seconds.", -1, 0, time.Second},
+	MsgIDSlowOn:                  {"You may send messages every {} seconds.", -1, 0, time.Second},
+	MsgIDTimeoutNoTimeout:        {"{} is not timed out from this channel.", 0, -1, 0},
+	MsgIDTimeoutSuccess:          {"{} has been timed out for {} seconds.", 0, 1, time.Second},
+	MsgIDUnbanSuccess:            {"{} is no longer banned from this channel.", 0, -1, 0},
+	MsgIDUnmodSuccess:            {"You have removed {} as a moderator of this channel.", 0, -1, 0},
+	MsgIDUntimeoutBanned:         {"{} is permanently banned.", 0, -1, 0},
+	MsgIDUntimeoutSuccess:        {"{} is no longer timed out in this channel.", 0, -1, 0},
+	MsgIDUnVIPSuccess:            {"You have removed {} as a VIP of this channel.", 0, -1, 0},
+	MsgIDVIPSuccess:              {"You have added {} as a VIP of this channel.", 0, -1, 0},
+}
+
+// parseNoticePayload fills in noticeMessage's Target and Duration from its text when the msg-id has a known payload.
+func parseNoticePayload(noticeMessage *NoticeMessage) {
+	var payload, ok = noticePayloads[NoticeMsgID(noticeMessage.MsgID)]
+	if !ok {
+		return
+	}
+	var captures = matchNoticeTemplate(payload.template, noticeMessage.Text)
+	if captures == nil {
+		return
+	}
+	if payload.target >= 0 {
+		noticeMessage.Target = strings.ToLower(captures[payload.target])
+	}
+	if payload.duration >= 0 {
+		noticeMessage.Duration = parseNoticeDuration(captures[payload.duration], payload.unit)
+	}
+}
+
+// matchNoticeTemplate returns the text matched by each {} in template, or nil if text doesn't match.
+// The template may match anywhere in text, and text may continue after it.
+func matchNoticeTemplate(template, text string) []string {
+	var literals = strings.Split(template, "{}")
+
+	var start = strings.Index(text, literals[0])
+	if start < 0 {
+		return nil
+	}
+	text = text[start+len(literals[0]):]
+
+	var captures []string
+	for i, literal := range literals[1:] {
+		var end = len(text)
+		if literal != "" {
+			end = strings.Index(text, literal)
+		} else if i < len(literals)-2 {
+			return nil // two captures in a row can't be split
+		}
+		if end < 0 {
+			return nil
+		}
+		captures = append(captures, strings.TrimSpace(text[:end]))
+		text = text[end+len(literal):]
+	}
+	return captures
+}
+
+// parseNoticeDuration parses "10", "10 seconds", "1 day", "2 weeks", etc. unit is used for bare numbers.
+func parseNoticeDuration(s string, unit time.Duration) time.Duration {
+	var fields = strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0
+	}
+	var n, err = strconv.Atoi(fields[0])
+	if err != nil {
+		return 0
+	}
+	if len(fields) == 2 {
+		switch strings.TrimSuffix(fields[1], "s") {
+		case "second":
+			unit = time.Second
+		case "minute":
+			unit = time.Minute
+		case "hour":
+			unit = time.Hour
+		case "day":
+			unit = time.Hour * 24
+		case "week":
+			unit = time.Hour * 24 * 7
+		case "month":
+			unit = time.Hour * 24 * 30
+		default:
+			return 0
+		}
+	}
+	return time.Duration(n) * unit
+}
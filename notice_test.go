@@ -0,0 +1,107 @@
+package tmi
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNoticeMsgIDCategory(t *testing.T) {
+	tests := []struct {
+		in   NoticeMsgID
+		want NoticeCategory
+	}{
+		{MsgIDBanSuccess, NoticeCommandSuccess},
+		{MsgIDAlreadyBanned, NoticeCommandError},
+		{MsgIDNoPermission, NoticePermissionDenied},
+		{MsgIDMsgRatelimit, NoticeRateLimited},
+		{MsgIDMsgChannelSuspended, NoticeChannelSuspended},
+		{MsgIDMsgBanned, NoticeBanned},
+		{MsgIDHostOn, NoticeInfo},
+		{"not_a_real_msg_id", NoticeUnknown},
+	}
+
+	for _, test := range tests {
+		got := test.in.Category()
+		if got != test.want {
+			t.Errorf("%v: got %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestNoticeErrorIs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{"@msg-id=no_permission :tmi.twitch.tv NOTICE #c :You don't have permission to perform that action.", ErrNoticePermissionDenied},
+		{"@msg-id=msg_ratelimit :tmi.twitch.tv NOTICE #c :Your message was not sent because you are sending messages too quickly.", ErrNoticeRateLimited},
+		{"@msg-id=msg_channel_suspended :tmi.twitch.tv NOTICE #c :This channel has been suspended.", ErrNoticeChannelSuspended},
+		{"@msg-id=msg_banned :tmi.twitch.tv NOTICE #c :You are permanently banned from talking in c.", ErrNoticeBanned},
+		{"@msg-id=already_banned :tmi.twitch.tv NOTICE #c :bob is already banned in this channel.", ErrNoticeCommandError},
+		{":tmi.twitch.tv NOTICE * :Login authentication failed", ErrLoginFailure},
+	}
+
+	for _, test := range tests {
+		ircData, _ := parseIRCMessage(test.in)
+		msg, _ := parseNoticeMessage(ircData)
+		err := msg.Err()
+		if !errors.Is(err, test.want) {
+			t.Errorf("%v: errors.Is(%v, %v) is false", msg.MsgID, err, test.want)
+		}
+		if errors.Is(err, ErrNoticeBanned) != (test.want == ErrNoticeBanned) {
+			t.Errorf("%v: errors.Is(%v, %v) should only match its own category", msg.MsgID, err, ErrNoticeBanned)
+		}
+	}
+
+	ircData, _ := parseIRCMessage("@msg-id=ban_success :tmi.twitch.tv NOTICE #c :bob is now banned from this channel.")
+	msg, _ := parseNoticeMessage(ircData)
+	if err := msg.Err(); err != nil {
+		t.Errorf("ban_success: got error %v, want nil", err)
+	}
+}
+
+func TestMatchNoticeTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		text     string
+		want     []string
+	}{
+		{"{} is now banned from this channel.", "bob is now banned from this channel.", []string{"bob"}},
+		{"{} has been timed out for {} seconds.", "bob has been timed out for 600 seconds.", []string{"bob", "600"}},
+		{"Invalid username: {}", "Invalid username: b0b!", []string{"b0b!"}},
+		{"You may send messages every {} seconds.", "This room is now in slow mode. You may send messages every 30 seconds.", []string{"30"}},
+		{"{} is now banned from this channel.", "something else entirely", nil},
+	}
+
+	for _, test := range tests {
+		got := matchNoticeTemplate(test.template, test.text)
+		if test.want == nil {
+			if got != nil {
+				t.Errorf("%v: got %v, want nil", test.text, got)
+			}
+			continue
+		}
+		assertStringSlicesEqual(t, test.text, got, test.want)
+	}
+}
+
+func TestParseNoticeDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		unit time.Duration
+		want time.Duration
+	}{
+		{"30", time.Second, time.Second * 30},
+		{"10", time.Minute, time.Minute * 10},
+		{"10 minutes", time.Second, time.Minute * 10},
+		{"1 day", time.Minute, time.Hour * 24},
+		{"2 weeks", time.Minute, time.Hour * 24 * 14},
+		{"3 months", time.Minute, time.Hour * 24 * 90},
+		{"soon", time.Second, 0},
+	}
+
+	for _, test := range tests {
+		assertDurationsEqual(t, test.in, parseNoticeDuration(test.in, test.unit), test.want)
+	}
+}
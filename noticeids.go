@@ -0,0 +1,335 @@
+// Code generated by gen_noticeids.go; DO NOT EDIT.
+
+package tmi
+
+// NOTICE msg-id values, see https://dev.twitch.tv/docs/irc/msg-id
+// login_failure and parse_error are set by the parser, Twitch does not send them.
+const (
+	MsgIDAlreadyBanned                  NoticeMsgID = "already_banned"
+	MsgIDAlreadyEmoteOnlyOff            NoticeMsgID = "already_emote_only_off"
+	MsgIDAlreadyEmoteOnlyOn             NoticeMsgID = "already_emote_only_on"
+	MsgIDAlreadyFollowersOff            NoticeMsgID = "already_followers_off"
+	MsgIDAlreadyFollowersOn             NoticeMsgID = "already_followers_on"
+	MsgIDAlreadyR9kOff                  NoticeMsgID = "already_r9k_off"
+	MsgIDAlreadyR9kOn                   NoticeMsgID = "already_r9k_on"
+	MsgIDAlreadySlowOff                 NoticeMsgID = "already_slow_off"
+	MsgIDAlreadySlowOn                  NoticeMsgID = "already_slow_on"
+	MsgIDAlreadySubsOff                 NoticeMsgID = "already_subs_off"
+	MsgIDAlreadySubsOn                  NoticeMsgID = "already_subs_on"
+	MsgIDAutohostReceive                NoticeMsgID = "autohost_receive"
+	MsgIDBadBanAdmin                    NoticeMsgID = "bad_ban_admin"
+	MsgIDBadBanAnon                     NoticeMsgID = "bad_ban_anon"
+	MsgIDBadBanBroadcaster              NoticeMsgID = "bad_ban_broadcaster"
+	MsgIDBadBanMod                      NoticeMsgID = "bad_ban_mod"
+	MsgIDBadBanSelf                     NoticeMsgID = "bad_ban_self"
+	MsgIDBadBanStaff                    NoticeMsgID = "bad_ban_staff"
+	MsgIDBadCommercialError             NoticeMsgID = "bad_commercial_error"
+	MsgIDBadDeleteMessageBroadcaster    NoticeMsgID = "bad_delete_message_broadcaster"
+	MsgIDBadDeleteMessageMod            NoticeMsgID = "bad_delete_message_mod"
+	MsgIDBadHostError                   NoticeMsgID = "bad_host_error"
+	MsgIDBadHostHosting                 NoticeMsgID = "bad_host_hosting"
+	MsgIDBadHostRateExceeded            NoticeMsgID = "bad_host_rate_exceeded"
+	MsgIDBadHostRejected                NoticeMsgID = "bad_host_rejected"
+	MsgIDBadHostSelf                    NoticeMsgID = "bad_host_self"
+	MsgIDBadModBanned                   NoticeMsgID = "bad_mod_banned"
+	MsgIDBadModMod                      NoticeMsgID = "bad_mod_mod"
+	MsgIDBadSlowDuration                NoticeMsgID = "bad_slow_duration"
+	MsgIDBadTimeoutAdmin                NoticeMsgID = "bad_timeout_admin"
+	MsgIDBadTimeoutAnon                 NoticeMsgID = "bad_timeout_anon"
+	MsgIDBadTimeoutBroadcaster          NoticeMsgID = "bad_timeout_broadcaster"
+	MsgIDBadTimeoutDuration             NoticeMsgID = "bad_timeout_duration"
+	MsgIDBadTimeoutMod                  NoticeMsgID = "bad_timeout_mod"
+	MsgIDBadTimeoutSelf                 NoticeMsgID = "bad_timeout_self"
+	MsgIDBadTimeoutStaff                NoticeMsgID = "bad_timeout_staff"
+	MsgIDBadUnbanNoBan                  NoticeMsgID = "bad_unban_no_ban"
+	MsgIDBadUnhostError                 NoticeMsgID = "bad_unhost_error"
+	MsgIDBadUnmodMod                    NoticeMsgID = "bad_unmod_mod"
+	MsgIDBadUnVIPGranteeNotVIP          NoticeMsgID = "bad_unvip_grantee_not_vip"
+	MsgIDBadVIPAchievementIncomplete    NoticeMsgID = "bad_vip_achievement_incomplete"
+	MsgIDBadVIPGranteeAlreadyVIP        NoticeMsgID = "bad_vip_grantee_already_vip"
+	MsgIDBadVIPGranteeBanned            NoticeMsgID = "bad_vip_grantee_banned"
+	MsgIDBadVIPMaxVIPsReached           NoticeMsgID = "bad_vip_max_vips_reached"
+	MsgIDBanSuccess                     NoticeMsgID = "ban_success"
+	MsgIDCmdsAvailable                  NoticeMsgID = "cmds_available"
+	MsgIDColorChanged                   NoticeMsgID = "color_changed"
+	MsgIDCommercialSuccess              NoticeMsgID = "commercial_success"
+	MsgIDDeleteMessageSuccess           NoticeMsgID = "delete_message_success"
+	MsgIDDeleteStaffMessageSuccess      NoticeMsgID = "delete_staff_message_success"
+	MsgIDEmoteOnlyOff                   NoticeMsgID = "emote_only_off"
+	MsgIDEmoteOnlyOn                    NoticeMsgID = "emote_only_on"
+	MsgIDFollowersOff                   NoticeMsgID = "followers_off"
+	MsgIDFollowersOn                    NoticeMsgID = "followers_on"
+	MsgIDFollowersOnZero                NoticeMsgID = "followers_on_zero"
+	MsgIDHostOff                        NoticeMsgID = "host_off"
+	MsgIDHostOn                         NoticeMsgID = "host_on"
+	MsgIDHostReceive                    NoticeMsgID = "host_receive"
+	MsgIDHostReceiveNoCount             NoticeMsgID = "host_receive_no_count"
+	MsgIDHostTargetWentOffline          NoticeMsgID = "host_target_went_offline"
+	MsgIDHostsRemaining                 NoticeMsgID = "hosts_remaining"
+	MsgIDInvalidUser                    NoticeMsgID = "invalid_user"
+	MsgIDLoginFailure                   NoticeMsgID = "login_failure"
+	MsgIDModSuccess                     NoticeMsgID = "mod_success"
+	MsgIDMsgBanned                      NoticeMsgID = "msg_banned"
+	MsgIDMsgBadCharacters               NoticeMsgID = "msg_bad_characters"
+	MsgIDMsgChannelBlocked              NoticeMsgID = "msg_channel_blocked"
+	MsgIDMsgChannelSuspended            NoticeMsgID = "msg_channel_suspended"
+	MsgIDMsgDuplicate                   NoticeMsgID = "msg_duplicate"
+	MsgIDMsgEmoteonly                   NoticeMsgID = "msg_emoteonly"
+	MsgIDMsgFollowersonly               NoticeMsgID = "msg_followersonly"
+	MsgIDMsgFollowersonlyFollowed       NoticeMsgID = "msg_followersonly_followed"
+	MsgIDMsgFollowersonlyZero           NoticeMsgID = "msg_followersonly_zero"
+	MsgIDMsgR9k                         NoticeMsgID = "msg_r9k"
+	MsgIDMsgRatelimit                   NoticeMsgID = "msg_ratelimit"
+	MsgIDMsgRejected                    NoticeMsgID = "msg_rejected"
+	MsgIDMsgRejectedMandatory           NoticeMsgID = "msg_rejected_mandatory"
+	MsgIDMsgRequiresVerifiedPhoneNumber NoticeMsgID = "msg_requires_verified_phone_number"
+	MsgIDMsgSlowmode                    NoticeMsgID = "msg_slowmode"
+	MsgIDMsgSubsonly                    NoticeMsgID = "msg_subsonly"
+	MsgIDMsgSuspended                   NoticeMsgID = "msg_suspended"
+	MsgIDMsgTimedout                    NoticeMsgID = "msg_timedout"
+	MsgIDMsgVerifiedEmail               NoticeMsgID = "msg_verified_email"
+	MsgIDNoHelp                         NoticeMsgID = "no_help"
+	MsgIDNoMods                         NoticeMsgID = "no_mods"
+	MsgIDNoPermission                   NoticeMsgID = "no_permission"
+	MsgIDNoVIPs                         NoticeMsgID = "no_vips"
+	MsgIDNotHosting                     NoticeMsgID = "not_hosting"
+	MsgIDParseError                     NoticeMsgID = "parse_error"
+	MsgIDR9kOff                         NoticeMsgID = "r9k_off"
+	MsgIDR9kOn                          NoticeMsgID = "r9k_on"
+	MsgIDRaidErrorAlreadyRaiding        NoticeMsgID = "raid_error_already_raiding"
+	MsgIDRaidErrorForbidden             NoticeMsgID = "raid_error_forbidden"
+	MsgIDRaidErrorSelf                  NoticeMsgID = "raid_error_self"
+	MsgIDRaidErrorTooManyViewers        NoticeMsgID = "raid_error_too_many_viewers"
+	MsgIDRaidErrorUnexpected            NoticeMsgID = "raid_error_unexpected"
+	MsgIDRaidNoticeMature               NoticeMsgID = "raid_notice_mature"
+	MsgIDRaidNoticeRestrictedChat       NoticeMsgID = "raid_notice_restricted_chat"
+	MsgIDRoomMods                       NoticeMsgID = "room_mods"
+	MsgIDSlowOff                        NoticeMsgID = "slow_off"
+	MsgIDSlowOn                         NoticeMsgID = "slow_on"
+	MsgIDSubsOff                        NoticeMsgID = "subs_off"
+	MsgIDSubsOn                         NoticeMsgID = "subs_on"
+	MsgIDTimeoutNoTimeout               NoticeMsgID = "timeout_no_timeout"
+	MsgIDTimeoutSuccess                 NoticeMsgID = "timeout_success"
+	MsgIDTOSBan                         NoticeMsgID = "tos_ban"
+	MsgIDTurboOnlyColor                 NoticeMsgID = "turbo_only_color"
+	MsgIDUnavailableCommand             NoticeMsgID = "unavailable_command"
+	MsgIDUnbanSuccess                   NoticeMsgID = "unban_success"
+	MsgIDUnmodSuccess                   NoticeMsgID = "unmod_success"
+	MsgIDUnraidErrorNoActiveRaid        NoticeMsgID = "unraid_error_no_active_raid"
+	MsgIDUnraidErrorUnexpected          NoticeMsgID = "unraid_error_unexpected"
+	MsgIDUnraidSuccess                  NoticeMsgID = "unraid_success"
+	MsgIDUnrecognizedCmd                NoticeMsgID = "unrecognized_cmd"
+	MsgIDUnsupportedChatroomsCmd        NoticeMsgID = "unsupported_chatrooms_cmd"
+	MsgIDUntimeoutBanned                NoticeMsgID = "untimeout_banned"
+	MsgIDUntimeoutSuccess               NoticeMsgID = "untimeout_success"
+	MsgIDUnVIPSuccess                   NoticeMsgID = "unvip_success"
+	MsgIDUsageBan                       NoticeMsgID = "usage_ban"
+	MsgIDUsageClear                     NoticeMsgID = "usage_clear"
+	MsgIDUsageColor                     NoticeMsgID = "usage_color"
+	MsgIDUsageCommercial                NoticeMsgID = "usage_commercial"
+	MsgIDUsageDisconnect                NoticeMsgID = "usage_disconnect"
+	MsgIDUsageDelete                    NoticeMsgID = "usage_delete"
+	MsgIDUsageEmoteOnlyOff              NoticeMsgID = "usage_emote_only_off"
+	MsgIDUsageEmoteOnlyOn               NoticeMsgID = "usage_emote_only_on"
+	MsgIDUsageFollowersOff              NoticeMsgID = "usage_followers_off"
+	MsgIDUsageFollowersOn               NoticeMsgID = "usage_followers_on"
+	MsgIDUsageHelp                      NoticeMsgID = "usage_help"
+	MsgIDUsageHost                      NoticeMsgID = "usage_host"
+	MsgIDUsageMarker                    NoticeMsgID = "usage_marker"
+	MsgIDUsageMe                        NoticeMsgID = "usage_me"
+	MsgIDUsageMod                       NoticeMsgID = "usage_mod"
+	MsgIDUsageMods                      NoticeMsgID = "usage_mods"
+	MsgIDUsageR9kOff                    NoticeMsgID = "usage_r9k_off"
+	MsgIDUsageR9kOn                     NoticeMsgID = "usage_r9k_on"
+	MsgIDUsageRaid                      NoticeMsgID = "usage_raid"
+	MsgIDUsageSlowOff                   NoticeMsgID = "usage_slow_off"
+	MsgIDUsageSlowOn                    NoticeMsgID = "usage_slow_on"
+	MsgIDUsageSubsOff                   NoticeMsgID = "usage_subs_off"
+	MsgIDUsageSubsOn                    NoticeMsgID = "usage_subs_on"
+	MsgIDUsageTimeout                   NoticeMsgID = "usage_timeout"
+	MsgIDUsageUnban                     NoticeMsgID = "usage_unban"
+	MsgIDUsageUnhost                    NoticeMsgID = "usage_unhost"
+	MsgIDUsageUnmod                     NoticeMsgID = "usage_unmod"
+	MsgIDUsageUnraid                    NoticeMsgID = "usage_unraid"
+	MsgIDUsageUntimeout                 NoticeMsgID = "usage_untimeout"
+	MsgIDUsageUnVIP                     NoticeMsgID = "usage_unvip"
+	MsgIDUsageUserColor                 NoticeMsgID = "usage_user_color"
+	MsgIDUsageVIP                       NoticeMsgID = "usage_vip"
+	MsgIDUsageVIPs                      NoticeMsgID = "usage_vips"
+	MsgIDUsageWhisper                   NoticeMsgID = "usage_whisper"
+	MsgIDVIPSuccess                     NoticeMsgID = "vip_success"
+	MsgIDVIPsSuccess                    NoticeMsgID = "vips_success"
+	MsgIDWhisperBanned                  NoticeMsgID = "whisper_banned"
+	MsgIDWhisperBannedRecipient         NoticeMsgID = "whisper_banned_recipient"
+	MsgIDWhisperInvalidLogin            NoticeMsgID = "whisper_invalid_login"
+	MsgIDWhisperInvalidSelf             NoticeMsgID = "whisper_invalid_self"
+	MsgIDWhisperLimitPerMin             NoticeMsgID = "whisper_limit_per_min"
+	MsgIDWhisperLimitPerSec             NoticeMsgID = "whisper_limit_per_sec"
+	MsgIDWhisperRestricted              NoticeMsgID = "whisper_restricted"
+	MsgIDWhisperRestrictedRecipient     NoticeMsgID = "whisper_restricted_recipient"
+)
+
+var noticeCategories = map[NoticeMsgID]NoticeCategory{
+	MsgIDAlreadyBanned:                  NoticeCommandError,
+	MsgIDAlreadyEmoteOnlyOff:            NoticeCommandError,
+	MsgIDAlreadyEmoteOnlyOn:             NoticeCommandError,
+	MsgIDAlreadyFollowersOff:            NoticeCommandError,
+	MsgIDAlreadyFollowersOn:             NoticeCommandError,
+	MsgIDAlreadyR9kOff:                  NoticeCommandError,
+	MsgIDAlreadyR9kOn:                   NoticeCommandError,
+	MsgIDAlreadySlowOff:                 NoticeCommandError,
+	MsgIDAlreadySlowOn:                  NoticeCommandError,
+	MsgIDAlreadySubsOff:                 NoticeCommandError,
+	MsgIDAlreadySubsOn:                  NoticeCommandError,
+	MsgIDAutohostReceive:                NoticeInfo,
+	MsgIDBadBanAdmin:                    NoticePermissionDenied,
+	MsgIDBadBanAnon:                     NoticePermissionDenied,
+	MsgIDBadBanBroadcaster:              NoticePermissionDenied,
+	MsgIDBadBanMod:                      NoticePermissionDenied,
+	MsgIDBadBanSelf:                     NoticeCommandError,
+	MsgIDBadBanStaff:                    NoticePermissionDenied,
+	MsgIDBadCommercialError:             NoticeCommandError,
+	MsgIDBadDeleteMessageBroadcaster:    NoticePermissionDenied,
+	MsgIDBadDeleteMessageMod:            NoticePermissionDenied,
+	MsgIDBadHostError:                   NoticeCommandError,
+	MsgIDBadHostHosting:                 NoticeCommandError,
+	MsgIDBadHostRateExceeded:            NoticeRateLimited,
+	MsgIDBadHostRejected:                NoticeCommandError,
+	MsgIDBadHostSelf:                    NoticeCommandError,
+	MsgIDBadModBanned:                   NoticeCommandError,
+	MsgIDBadModMod:                      NoticeCommandError,
+	MsgIDBadSlowDuration:                NoticeCommandError,
+	MsgIDBadTimeoutAdmin:                NoticePermissionDenied,
+	MsgIDBadTimeoutAnon:                 NoticePermissionDenied,
+	MsgIDBadTimeoutBroadcaster:          NoticePermissionDenied,
+	MsgIDBadTimeoutDuration:             NoticeCommandError,
+	MsgIDBadTimeoutMod:                  NoticePermissionDenied,
+	MsgIDBadTimeoutSelf:                 NoticeCommandError,
+	MsgIDBadTimeoutStaff:                NoticePermissionDenied,
+	MsgIDBadUnbanNoBan:                  NoticeCommandError,
+	MsgIDBadUnhostError:                 NoticeCommandError,
+	MsgIDBadUnmodMod:                    NoticeCommandError,
+	MsgIDBadUnVIPGranteeNotVIP:          NoticeCommandError,
+	MsgIDBadVIPAchievementIncomplete:    NoticeCommandError,
+	MsgIDBadVIPGranteeAlreadyVIP:        NoticeCommandError,
+	MsgIDBadVIPGranteeBanned:            NoticeCommandError,
+	MsgIDBadVIPMaxVIPsReached:           NoticeCommandError,
+	MsgIDBanSuccess:                     NoticeCommandSuccess,
+	MsgIDCmdsAvailable:                  NoticeInfo,
+	MsgIDColorChanged:                   NoticeCommandSuccess,
+	MsgIDCommercialSuccess:              NoticeCommandSuccess,
+	MsgIDDeleteMessageSuccess:           NoticeCommandSuccess,
+	MsgIDDeleteStaffMessageSuccess:      NoticeCommandSuccess,
+	MsgIDEmoteOnlyOff:                   NoticeCommandSuccess,
+	MsgIDEmoteOnlyOn:                    NoticeCommandSuccess,
+	MsgIDFollowersOff:                   NoticeCommandSuccess,
+	MsgIDFollowersOn:                    NoticeCommandSuccess,
+	MsgIDFollowersOnZero:                NoticeCommandSuccess,
+	MsgIDHostOff:                        NoticeInfo,
+	MsgIDHostOn:                         NoticeInfo,
+	MsgIDHostReceive:                    NoticeInfo,
+	MsgIDHostReceiveNoCount:             NoticeInfo,
+	MsgIDHostTargetWentOffline:          NoticeInfo,
+	MsgIDHostsRemaining:                 NoticeInfo,
+	MsgIDInvalidUser:                    NoticeCommandError,
+	MsgIDLoginFailure:                   NoticePermissionDenied,
+	MsgIDModSuccess:                     NoticeCommandSuccess,
+	MsgIDMsgBanned:                      NoticeBanned,
+	MsgIDMsgBadCharacters:               NoticeCommandError,
+	MsgIDMsgChannelBlocked:              NoticeChannelSuspended,
+	MsgIDMsgChannelSuspended:            NoticeChannelSuspended,
+	MsgIDMsgDuplicate:                   NoticeCommandError,
+	MsgIDMsgEmoteonly:                   NoticeCommandError,
+	MsgIDMsgFollowersonly:               NoticeCommandError,
+	MsgIDMsgFollowersonlyFollowed:       NoticeCommandError,
+	MsgIDMsgFollowersonlyZero:           NoticeCommandError,
+	MsgIDMsgR9k:                         NoticeCommandError,
+	MsgIDMsgRatelimit:                   NoticeRateLimited,
+	MsgIDMsgRejected:                    NoticeCommandError,
+	MsgIDMsgRejectedMandatory:           NoticeCommandError,
+	MsgIDMsgRequiresVerifiedPhoneNumber: NoticePermissionDenied,
+	MsgIDMsgSlowmode:                    NoticeRateLimited,
+	MsgIDMsgSubsonly:                    NoticeCommandError,
+	MsgIDMsgSuspended:                   NoticeBanned,
+	MsgIDMsgTimedout:                    NoticeBanned,
+	MsgIDMsgVerifiedEmail:               NoticePermissionDenied,
+	MsgIDNoHelp:                         NoticeInfo,
+	MsgIDNoMods:                         NoticeCommandSuccess,
+	MsgIDNoPermission:                   NoticePermissionDenied,
+	MsgIDNoVIPs:                         NoticeCommandSuccess,
+	MsgIDNotHosting:                     NoticeCommandError,
+	MsgIDParseError:                     NoticeInfo,
+	MsgIDR9kOff:                         NoticeCommandSuccess,
+	MsgIDR9kOn:                          NoticeCommandSuccess,
+	MsgIDRaidErrorAlreadyRaiding:        NoticeCommandError,
+	MsgIDRaidErrorForbidden:             NoticePermissionDenied,
+	MsgIDRaidErrorSelf:                  NoticeCommandError,
+	MsgIDRaidErrorTooManyViewers:        NoticeCommandError,
+	MsgIDRaidErrorUnexpected:            NoticeCommandError,
+	MsgIDRaidNoticeMature:               NoticeInfo,
+	MsgIDRaidNoticeRestrictedChat:       NoticeInfo,
+	MsgIDRoomMods:                       NoticeCommandSuccess,
+	MsgIDSlowOff:                        NoticeCommandSuccess,
+	MsgIDSlowOn:                         NoticeCommandSuccess,
+	MsgIDSubsOff:                        NoticeCommandSuccess,
+	MsgIDSubsOn:                         NoticeCommandSuccess,
+	MsgIDTimeoutNoTimeout:               NoticeCommandError,
+	MsgIDTimeoutSuccess:                 NoticeCommandSuccess,
+	MsgIDTOSBan:                         NoticeChannelSuspended,
+	MsgIDTurboOnlyColor:                 NoticePermissionDenied,
+	MsgIDUnavailableCommand:             NoticeCommandError,
+	MsgIDUnbanSuccess:                   NoticeCommandSuccess,
+	MsgIDUnmodSuccess:                   NoticeCommandSuccess,
+	MsgIDUnraidErrorNoActiveRaid:        NoticeCommandError,
+	MsgIDUnraidErrorUnexpected:          NoticeCommandError,
+	MsgIDUnraidSuccess:                  NoticeCommandSuccess,
+	MsgIDUnrecognizedCmd:                NoticeCommandError,
+	MsgIDUnsupportedChatroomsCmd:        NoticeCommandError,
+	MsgIDUntimeoutBanned:                NoticeCommandError,
+	MsgIDUntimeoutSuccess:               NoticeCommandSuccess,
+	MsgIDUnVIPSuccess:                   NoticeCommandSuccess,
+	MsgIDUsageBan:                       NoticeCommandError,
+	MsgIDUsageClear:                     NoticeCommandError,
+	MsgIDUsageColor:                     NoticeCommandError,
+	MsgIDUsageCommercial:                NoticeCommandError,
+	MsgIDUsageDisconnect:                NoticeCommandError,
+	MsgIDUsageDelete:                    NoticeCommandError,
+	MsgIDUsageEmoteOnlyOff:              NoticeCommandError,
+	MsgIDUsageEmoteOnlyOn:               NoticeCommandError,
+	MsgIDUsageFollowersOff:              NoticeCommandError,
+	MsgIDUsageFollowersOn:               NoticeCommandError,
+	MsgIDUsageHelp:                      NoticeCommandError,
+	MsgIDUsageHost:                      NoticeCommandError,
+	MsgIDUsageMarker:                    NoticeCommandError,
+	MsgIDUsageMe:                        NoticeCommandError,
+	MsgIDUsageMod:                       NoticeCommandError,
+	MsgIDUsageMods:                      NoticeCommandError,
+	MsgIDUsageR9kOff:                    NoticeCommandError,
+	MsgIDUsageR9kOn:                     NoticeCommandError,
+	MsgIDUsageRaid:                      NoticeCommandError,
+	MsgIDUsageSlowOff:                   NoticeCommandError,
+	MsgIDUsageSlowOn:                    NoticeCommandError,
+	MsgIDUsageSubsOff:                   NoticeCommandError,
+	MsgIDUsageSubsOn:                    NoticeCommandError,
+	MsgIDUsageTimeout:                   NoticeCommandError,
+	MsgIDUsageUnban:                     NoticeCommandError,
+	MsgIDUsageUnhost:                    NoticeCommandError,
+	MsgIDUsageUnmod:                     NoticeCommandError,
+	MsgIDUsageUnraid:                    NoticeCommandError,
+	MsgIDUsageUntimeout:                 NoticeCommandError,
+	MsgIDUsageUnVIP:                     NoticeCommandError,
+	MsgIDUsageUserColor:                 NoticeCommandError,
+	MsgIDUsageVIP:                       NoticeCommandError,
+	MsgIDUsageVIPs:                      NoticeCommandError,
+	MsgIDUsageWhisper:                   NoticeCommandError,
+	MsgIDVIPSuccess:                     NoticeCommandSuccess,
+	MsgIDVIPsSuccess:                    NoticeCommandSuccess,
+	MsgIDWhisperBanned:                  NoticeBanned,
+	MsgIDWhisperBannedRecipient:         NoticeCommandError,
+	MsgIDWhisperInvalidLogin:            NoticeCommandError,
+	MsgIDWhisperInvalidSelf:             NoticeCommandError,
+	MsgIDWhisperLimitPerMin:             NoticeRateLimited,
+	MsgIDWhisperLimitPerSec:             NoticeRateLimited,
+	MsgIDWhisperRestricted:              NoticePermissionDenied,
+	MsgIDWhisperRestrictedRecipient:     NoticeCommandError,
+}
@@ -2,9 +2,11 @@ package tmi
 
 import (
 	"errors"
+	"math"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf16"
 )
 
 // EscapeIRCTagValues escapes strings in certain messages' IRCTags `\s` -> " ", `\n` -> "\n", `\r` -> "\r", `\:` -> ";", `\\` -> "\\"
@@ -175,6 +177,7 @@ func parseClearChatMessage(data IRCData) ClearChatMessage {
 		Data:    data,
 		IRCType: data.Command,
 		Type:    CLEARCHAT,
+		Source:  parseSharedChatSource(data.Tags),
 	}
 	if len(data.Params) > 0 {
 		clearChatMessage.Channel = data.Params[0]
@@ -229,6 +232,7 @@ func parseClearMsgMessage(data IRCData) ClearMsgMessage {
 		IRCType:     data.Command,
 		Type:        CLEARMSG,
 		Login:       data.Tags["login"],
+		Source:      parseSharedChatSource(data.Tags),
 		TargetMsgID: data.Tags["target-msg-id"],
 	}
 	if len(data.Params) > 0 {
@@ -390,19 +394,26 @@ func parseNoticeMessage(data IRCData) (NoticeMessage, error) {
 				}
 			}
 
-		// Listen for ROOMSTATE followers notice instead (includes delay)
+		// Followers only mode on/off, ROOMSTATE also includes the delay
 		case "followers_off":
-		case "followers_on":
-		case "followers_onzero":
+			noticeMessage.Enabled = false
+		case "followers_on",
+			"followers_on_zero":
+			noticeMessage.Enabled = true
 
 		// Listen for HOSTTARGET instead
 		case "host_off":
 		case "host_on":
 
-		// Listen for ROOMSTATE slowmode notice instead (includes delay)
+		// Slow mode on/off, ROOMSTATE also includes the delay
 		case "slow_off":
+			noticeMessage.Enabled = false
 		case "slow_on":
+			noticeMessage.Enabled = true
 		}
+
+		noticeMessage.Category = NoticeMsgID(msgID).Category()
+		parseNoticePayload(&noticeMessage)
 	} else {
 		loginFailures := []string{
 			"Login unsuccessful",
@@ -413,11 +424,13 @@ func parseNoticeMessage(data IRCData) (NoticeMessage, error) {
 		}
 		for _, failure := range loginFailures {
 			if strings.Contains(msg, failure) {
-				noticeMessage.MsgID = "login_failure"
+				noticeMessage.MsgID = string(MsgIDLoginFailure)
+				noticeMessage.Category = NoticePermissionDenied
 				return noticeMessage, ErrLoginFailure
 			}
 		}
-		noticeMessage.MsgID = "parse_error"
+		noticeMessage.MsgID = string(MsgIDParseError)
+		noticeMessage.Category = NoticeInfo
 	}
 
 	return noticeMessage, nil
@@ -436,6 +449,7 @@ func parseRoomstateMessage(data IRCData) RoomstateMessage {
 		Data:    data,
 		IRCType: data.Command,
 		Type:    ROOMSTATE,
+		RoomID:  data.Tags["room-id"],
 		States:  make(map[string]RoomState),
 	}
 	if len(data.Params) > 0 {
@@ -480,6 +494,29 @@ func parseRoomstateMessage(data IRCData) RoomstateMessage {
 	return roomstateMessage
 }
 
+// Merge returns the settings after applying the states present in m, states missing from m are left unchanged.
+func (rs RoomSettings) Merge(m RoomstateMessage) RoomSettings {
+	if m.RoomID != "" {
+		rs.RoomID = m.RoomID
+	}
+	for tag, state := range m.States {
+		switch tag {
+		case "emote-only":
+			rs.EmoteOnly = state.Enabled
+		case "followers-only":
+			rs.FollowersOnly = state.Enabled
+			rs.FollowersDuration = state.Delay
+		case "r9k":
+			rs.R9K = state.Enabled
+		case "slow":
+			rs.Slow = state.Delay
+		case "subs-only":
+			rs.SubsOnly = state.Enabled
+		}
+	}
+	return rs
+}
+
 func parseUsernoticeMessage(data IRCData) UsernoticeMessage {
 	var usernoticeMessage = UsernoticeMessage{
 		Data:      data,
@@ -488,6 +525,7 @@ func parseUsernoticeMessage(data IRCData) UsernoticeMessage {
 		ID:        data.Tags["id"],
 		MsgID:     data.Tags["msg-id"],
 		MsgParams: make(IRCTags),
+		Source:    parseSharedChatSource(data.Tags),
 		SystemMsg: data.Tags["system-msg"],
 		User:      parseUser(data.Tags, data.Prefix),
 	}
@@ -540,6 +578,21 @@ func parseNamesMessage(data IRCData) NamesMessage {
 	return namesMessage
 }
 
+func parseEndOfNamesMessage(data IRCData) EndOfNamesMessage {
+	var endOfNamesMessage = EndOfNamesMessage{
+		Data:    data,
+		IRCType: data.Command,
+		Type:    ENDOFNAMES,
+	}
+
+	if len(data.Params) == 3 {
+		endOfNamesMessage.Channel = data.Params[1]
+		endOfNamesMessage.Text = data.Params[2]
+	}
+
+	return endOfNamesMessage
+}
+
 func parseJoinMessage(data IRCData) JoinMessage {
 	var joinMessage = JoinMessage{
 		Data:     data,
@@ -590,14 +643,25 @@ func parsePongMessage(data IRCData) PongMessage {
 	return pongMessage
 }
 
-func parsePrivateMessage(data IRCData) PrivateMessage {
+// parsePrivateMessage parses a PRIVMSG, finding cheermotes with cheermotePrefixes, or DefaultCheermotePrefixes if nil.
+func parsePrivateMessage(data IRCData, cheermotePrefixes []string) PrivateMessage {
 	var privateMessage = PrivateMessage{
-		Data:    data,
-		IRCType: data.Command,
-		Type:    PRIVMSG,
-		ID:      data.Tags["id"],
-		User:    parseUser(data.Tags, data.Prefix),
-	}
+		Data:             data,
+		IRCType:          data.Command,
+		Type:             PRIVMSG,
+		ClientNonce:      data.Tags["client-nonce"],
+		CustomRewardID:   data.Tags["custom-reward-id"],
+		FirstMessage:     data.Tags["first-msg"] == "1",
+		ID:               data.Tags["id"],
+		MsgID:            data.Tags["msg-id"],
+		ReturningChatter: data.Tags["returning-chatter"] == "1",
+		RoomID:           data.Tags["room-id"],
+		SentTime:         ParseTimeStamp(data.Tags["tmi-sent-ts"]),
+		Source:           parseSharedChatSource(data.Tags),
+		User:             parseUser(data.Tags, data.Prefix),
+	}
+	privateMessage.Highlighted = privateMessage.MsgID == "highlighted-message"
+	privateMessage.SkipSubsMode = privateMessage.MsgID == "skip-subs-mode-message"
 	if len(data.Params) > 0 {
 		privateMessage.Channel = data.Params[0]
 	}
@@ -612,20 +676,43 @@ func parsePrivateMessage(data IRCData) PrivateMessage {
 	}
 
 	privateMessage.Emotes = parseEmotes(data.Tags["emotes"], privateMessage.Text)
+	privateMessage.Flags = parseAutoModFlags(data.Tags["flags"], privateMessage.Text)
 
 	if bits, ok := data.Tags["bits"]; ok {
 		if val, err := strconv.Atoi(bits); err == nil {
 			privateMessage.Bits = val
 		}
 	}
+	if privateMessage.Bits > 0 {
+		if cheermotePrefixes == nil {
+			cheermotePrefixes = DefaultCheermotePrefixes
+		}
+		privateMessage.Cheermotes = ParseCheermotes(privateMessage.Text, cheermotePrefixes)
+	}
 
 	if _, ok := data.Tags["reply-parent-msg-id"]; ok {
 		privateMessage.Reply = true
 	}
 
+	if amount, ok := data.Tags["pinned-chat-paid-amount"]; ok {
+		var hypeChat = HypeChat{
+			Currency:        data.Tags["pinned-chat-paid-currency"],
+			Level:           data.Tags["pinned-chat-paid-level"],
+			IsSystemMessage: data.Tags["pinned-chat-paid-is-system-message"] == "1",
+		}
+		hypeChat.Amount, _ = strconv.Atoi(amount)
+		hypeChat.Exponent, _ = strconv.Atoi(data.Tags["pinned-chat-paid-exponent"])
+		privateMessage.HypeChat = &hypeChat
+	}
+
 	return privateMessage
 }
 
+// Value returns the Hype Chat amount in the currency's major unit, e.g. 1.5 for 150 with an exponent of 2.
+func (h HypeChat) Value() float64 {
+	return float64(h.Amount) / math.Pow10(h.Exponent)
+}
+
 func parseWhisperMessage(data IRCData) WhisperMessage {
 	var whisperMessage = WhisperMessage{
 		Data:    data,
@@ -647,6 +734,65 @@ func parseWhisperMessage(data IRCData) WhisperMessage {
 	return whisperMessage
 }
 
+func parseCapMessage(data IRCData) CapMessage {
+	var capMessage = CapMessage{
+		Data:         data,
+		IRCType:      data.Command,
+		Type:         CAP,
+		Capabilities: []string{},
+	}
+	if len(data.Params) > 1 {
+		capMessage.Subcommand = data.Params[1]
+	}
+	if len(data.Params) > 2 {
+		capMessage.Capabilities = strings.Fields(data.Params[2])
+	}
+	return capMessage
+}
+
+func parseWelcomeMessage(data IRCData) WelcomeMessage {
+	var welcomeMessage = WelcomeMessage{
+		Data:    data,
+		IRCType: data.Command,
+		Type:    WELCOME,
+	}
+	if len(data.Params) > 0 {
+		welcomeMessage.Nick = data.Params[0]
+	}
+	if len(data.Params) > 1 {
+		welcomeMessage.Text = data.Params[len(data.Params)-1]
+	}
+	return welcomeMessage
+}
+
+func parseMotdMessage(data IRCData) MotdMessage {
+	var motdMessage = MotdMessage{
+		Data:    data,
+		IRCType: data.Command,
+		Type:    MOTD,
+	}
+	if len(data.Params) > 0 {
+		motdMessage.Nick = data.Params[0]
+	}
+	if len(data.Params) > 1 {
+		motdMessage.Text = data.Params[len(data.Params)-1]
+	}
+	return motdMessage
+}
+
+func parseUnknownCommandMessage(data IRCData) UnknownCommandMessage {
+	var unknownCommandMessage = UnknownCommandMessage{
+		Data:    data,
+		IRCType: data.Command,
+		Type:    UNKNOWNCOMMAND,
+	}
+	if len(data.Params) == 3 {
+		unknownCommandMessage.Command = data.Params[1]
+		unknownCommandMessage.Text = data.Params[2]
+	}
+	return unknownCommandMessage
+}
+
 func parseUser(tags IRCTags, prefix string) *User {
 	var user = User{
 		BadgeInfo:   tags["badge-info"],
@@ -668,6 +814,10 @@ func parseUser(tags IRCTags, prefix string) *User {
 	}
 
 	user.Badges = parseBadges(tags["badges"])
+	user.BadgeInfos = parseBadges(tags["badge-info"])
+	for i := range user.BadgeInfos {
+		user.BadgeInfos[i].Version = escapeIRCTag(user.BadgeInfos[i].Version)
+	}
 	for _, badge := range user.Badges {
 		if badge.Name == "broadcaster" {
 			user.Broadcaster = true
@@ -689,6 +839,52 @@ func parseUser(tags IRCTags, prefix string) *User {
 	return &user
 }
 
+// Badge returns the user's badge called name, and false if the user doesn't have it.
+func (u *User) Badge(name string) (Badge, bool) {
+	for _, badge := range u.Badges {
+		if badge.Name == name {
+			return badge, true
+		}
+	}
+	return Badge{}, false
+}
+
+// HasBadge reports whether the user has a badge called name.
+func (u *User) HasBadge(name string) bool {
+	var _, ok = u.Badge(name)
+	return ok
+}
+
+// IsFounder reports whether the user has the founder badge.
+func (u *User) IsFounder() bool {
+	return u.HasBadge("founder")
+}
+
+// SubscriberMonths returns the exact number of months the user has been subscribed, from the
+// subscriber or founder badge-info. It is 0 if the user is not subscribed.
+func (u *User) SubscriberMonths() int {
+	for _, info := range u.BadgeInfos {
+		if info.Name == "subscriber" || info.Name == "founder" {
+			return info.Value
+		}
+	}
+	return 0
+}
+
+// PredictionOutcome returns the outcome the user predicted, e.g. "blue-1" or the outcome's title,
+// and false if the user has not made a prediction.
+func (u *User) PredictionOutcome() (string, bool) {
+	for _, info := range u.BadgeInfos {
+		if info.Name == "predictions" && info.Version != "" {
+			return info.Version, true
+		}
+	}
+	if badge, ok := u.Badge("predictions"); ok {
+		return badge.Version, true
+	}
+	return "", false
+}
+
 func parseUsernameFromPrefix(prefix string) string {
 	var username string
 	if prefix != "" {
@@ -705,6 +901,25 @@ func parseUsernameFromPrefix(prefix string) string {
 	return username
 }
 
+// parseSharedChatSource returns nil if the message has no source-room-id tag.
+func parseSharedChatSource(tags IRCTags) *SharedChatSource {
+	var roomID, ok = tags["source-room-id"]
+	if !ok {
+		return nil
+	}
+	var source = SharedChatSource{
+		Badges:       parseBadges(tags["source-badges"]),
+		BadgeInfos:   parseBadges(tags["source-badge-info"]),
+		ID:           tags["source-id"],
+		OtherChannel: roomID != tags["room-id"],
+		RoomID:       roomID,
+	}
+	for i := range source.BadgeInfos {
+		source.BadgeInfos[i].Version = escapeIRCTag(source.BadgeInfos[i].Version)
+	}
+	return &source
+}
+
 func parseBadges(rawBadges string) []Badge {
 	var badges []Badge
 	if rawBadges == "" {
@@ -720,6 +935,7 @@ func parseBadges(rawBadges string) []Badge {
 		}
 		var badge Badge
 		badge.Name = pair[0]
+		badge.Version = pair[1]
 		if val, err := strconv.Atoi(pair[1]); err == nil {
 			badge.Value = val
 		}
@@ -735,7 +951,8 @@ func parseEmotes(rawEmotes, message string) []Emote {
 		return emotes
 	}
 
-	msg := []rune(message)
+	// Twitch's emote positions count UTF-16 code units, not bytes or runes.
+	msg := utf16.Encode([]rune(message))
 
 	var splEmotes = strings.Split(rawEmotes, "/")
 
@@ -776,18 +993,24 @@ parseLoop:
 			})
 		}
 
-		var nameStartIdx = positions[0].StartIdx
-		if nameStartIdx+1 > len(msg) {
-			nameStartIdx = len(msg) - 1
-		}
-		var nameEndIdx = positions[0].EndIdx
-		if nameEndIdx+1 > len(msg) {
-			nameEndIdx = len(msg) - 1
+		var name string
+		if len(msg) > 0 {
+			var nameStartIdx = positions[0].StartIdx
+			if nameStartIdx+1 > len(msg) {
+				nameStartIdx = len(msg) - 1
+			}
+			var nameEndIdx = positions[0].EndIdx
+			if nameEndIdx+1 > len(msg) {
+				nameEndIdx = len(msg) - 1
+			}
+			if nameStartIdx <= nameEndIdx {
+				name = string(utf16.Decode(msg[nameStartIdx : nameEndIdx+1]))
+			}
 		}
 
 		emotes = append(emotes, Emote{
 			ID:        spl[0],
-			Name:      string(msg[nameStartIdx : nameEndIdx+1]),
+			Name:      name,
 			Positions: positions,
 		})
 	}
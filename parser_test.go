@@ -374,7 +374,7 @@ func TestParseGlobalUserstateMessage(t *testing.T) {
 				User: &User{
 					BadgeInfo: "<badge-info>",
 					Badges: []Badge{
-						{"<badge>", 1},
+						{"<badge>", 1, "1"},
 					},
 					Broadcaster: false,
 					Color:       "<color>",
@@ -398,7 +398,7 @@ func TestParseGlobalUserstateMessage(t *testing.T) {
 				User: &User{
 					BadgeInfo: "subscriber/8",
 					Badges: []Badge{
-						{"subscriber", 6},
+						{"subscriber", 6, "6"},
 					},
 					Broadcaster: false,
 					Color:       "#0D4200",
@@ -502,113 +502,182 @@ func TestParseNoticeMessage(t *testing.T) {
 		{
 			"@msg-id=<msg-id> :tmi.twitch.tv NOTICE #<channel> :<message>",
 			NoticeMessage{
-				Channel: "#<channel>",
-				IRCType: "NOTICE",
-				Text:    "<message>",
-				Type:    NOTICE,
-				Enabled: false,
-				Mods:    []string{},
-				MsgID:   "<msg-id>",
-				Notice:  "notice",
-				VIPs:    []string{},
+				Channel:  "#<channel>",
+				IRCType:  "NOTICE",
+				Text:     "<message>",
+				Type:     NOTICE,
+				Category: NoticeUnknown,
+				Enabled:  false,
+				Mods:     []string{},
+				MsgID:    "<msg-id>",
+				Notice:   "notice",
+				VIPs:     []string{},
 			},
 		},
 		{
 			"@msg-id=slow_off :tmi.twitch.tv NOTICE #dallas :This room is no longer in slow mode.",
 			NoticeMessage{
-				Channel: "#dallas",
-				IRCType: "NOTICE",
-				Text:    "This room is no longer in slow mode.",
-				Type:    NOTICE,
-				Enabled: false,
-				Mods:    []string{},
-				MsgID:   "slow_off",
-				Notice:  "notice",
-				VIPs:    []string{},
+				Channel:  "#dallas",
+				IRCType:  "NOTICE",
+				Text:     "This room is no longer in slow mode.",
+				Type:     NOTICE,
+				Category: NoticeCommandSuccess,
+				Enabled:  false,
+				Mods:     []string{},
+				MsgID:    "slow_off",
+				Notice:   "notice",
+				VIPs:     []string{},
 			},
 		},
 		{
 			"@msg-id=r9k_on :tmi.twitch.tv NOTICE #achannel :This room is now in unique-chat mode.",
 			NoticeMessage{
-				Channel: "#achannel",
-				IRCType: "NOTICE",
-				Text:    "This room is now in unique-chat mode.",
-				Type:    NOTICE,
-				Enabled: true,
-				Mods:    []string{},
-				MsgID:   "r9k_on",
-				Notice:  "uniquechat",
-				VIPs:    []string{},
+				Channel:  "#achannel",
+				IRCType:  "NOTICE",
+				Text:     "This room is now in unique-chat mode.",
+				Type:     NOTICE,
+				Category: NoticeCommandSuccess,
+				Enabled:  true,
+				Mods:     []string{},
+				MsgID:    "r9k_on",
+				Notice:   "uniquechat",
+				VIPs:     []string{},
 			},
 		},
 		{
 			"@msg-id=r9k_off :tmi.twitch.tv NOTICE #somechannel :This room is no longer in unique-chat mode.",
 			NoticeMessage{
-				Channel: "#somechannel",
-				IRCType: "NOTICE",
-				Text:    "This room is no longer in unique-chat mode.",
-				Type:    NOTICE,
-				Enabled: false,
-				Mods:    []string{},
-				MsgID:   "r9k_off",
-				Notice:  "uniquechat",
-				VIPs:    []string{},
+				Channel:  "#somechannel",
+				IRCType:  "NOTICE",
+				Text:     "This room is no longer in unique-chat mode.",
+				Type:     NOTICE,
+				Category: NoticeCommandSuccess,
+				Enabled:  false,
+				Mods:     []string{},
+				MsgID:    "r9k_off",
+				Notice:   "uniquechat",
+				VIPs:     []string{},
 			},
 		},
 		{
 			"@msg-id=emote_only_on :tmi.twitch.tv NOTICE #ch :This room is now in emote-only mode.",
 			NoticeMessage{
-				Channel: "#ch",
-				IRCType: "NOTICE",
-				Text:    "This room is now in emote-only mode.",
-				Type:    NOTICE,
-				Enabled: true,
-				Mods:    []string{},
-				MsgID:   "emote_only_on",
-				Notice:  "emoteonly",
-				VIPs:    []string{},
+				Channel:  "#ch",
+				IRCType:  "NOTICE",
+				Text:     "This room is now in emote-only mode.",
+				Type:     NOTICE,
+				Category: NoticeCommandSuccess,
+				Enabled:  true,
+				Mods:     []string{},
+				MsgID:    "emote_only_on",
+				Notice:   "emoteonly",
+				VIPs:     []string{},
 			},
 		},
 		{
 			"@msg-id=emote_only_off :tmi.twitch.tv NOTICE #itsachannel :This room is no longer in emote-only mode.",
 			NoticeMessage{
-				Channel: "#itsachannel",
-				IRCType: "NOTICE",
-				Text:    "This room is no longer in emote-only mode.",
-				Type:    NOTICE,
-				Enabled: false,
-				Mods:    []string{},
-				MsgID:   "emote_only_off",
-				Notice:  "emoteonly",
-				VIPs:    []string{},
+				Channel:  "#itsachannel",
+				IRCType:  "NOTICE",
+				Text:     "This room is no longer in emote-only mode.",
+				Type:     NOTICE,
+				Category: NoticeCommandSuccess,
+				Enabled:  false,
+				Mods:     []string{},
+				MsgID:    "emote_only_off",
+				Notice:   "emoteonly",
+				VIPs:     []string{},
 			},
 		},
 		{
 			"@msg-id=subs_on :tmi.twitch.tv NOTICE #yep :This room is now in subscribers-only mode.",
 			NoticeMessage{
-				Channel: "#yep",
-				IRCType: "NOTICE",
-				Text:    "This room is now in subscribers-only mode.",
-				Type:    NOTICE,
-				Enabled: true,
-				Mods:    []string{},
-				MsgID:   "subs_on",
-				Notice:  "subonly",
-				VIPs:    []string{},
+				Channel:  "#yep",
+				IRCType:  "NOTICE",
+				Text:     "This room is now in subscribers-only mode.",
+				Type:     NOTICE,
+				Category: NoticeCommandSuccess,
+				Enabled:  true,
+				Mods:     []string{},
+				MsgID:    "subs_on",
+				Notice:   "subonly",
+				VIPs:     []string{},
 			},
 		},
 		{
 			"@msg-id=subs_off :tmi.twitch.tv NOTICE #nope :This room is no longer in subscribers-only mode.",
 			NoticeMessage{
-				Channel: "#nope",
-				IRCType: "NOTICE",
-				Text:    "This room is no longer in subscribers-only mode.",
-				Type:    NOTICE,
-				Enabled: false,
-				Mods:    []string{},
-				MsgID:   "subs_off",
-				Notice:  "subonly",
-				VIPs:    []string{},
+				Channel:  "#nope",
+				IRCType:  "NOTICE",
+				Text:     "This room is no longer in subscribers-only mode.",
+				Type:     NOTICE,
+				Category: NoticeCommandSuccess,
+				Enabled:  false,
+				Mods:     []string{},
+				MsgID:    "subs_off",
+				Notice:   "subonly",
+				VIPs:     []string{},
+			},
+		},
+		{
+			"@msg-id=timeout_success :tmi.twitch.tv NOTICE #mychannel :Bobby has been timed out for 600 seconds.",
+			NoticeMessage{
+				Channel:  "#mychannel",
+				IRCType:  "NOTICE",
+				Text:     "Bobby has been timed out for 600 seconds.",
+				Type:     NOTICE,
+				Category: NoticeCommandSuccess,
+				Duration: time.Minute * 10,
+				Mods:     []string{},
+				MsgID:    "timeout_success",
+				Notice:   "notice",
+				Target:   "bobby",
+				VIPs:     []string{},
+			},
+		},
+		{
+			"@msg-id=slow_on :tmi.twitch.tv NOTICE #slowpoke :This room is now in slow mode. You may send messages every 30 seconds.",
+			NoticeMessage{
+				Channel:  "#slowpoke",
+				IRCType:  "NOTICE",
+				Text:     "This room is now in slow mode. You may send messages every 30 seconds.",
+				Type:     NOTICE,
+				Category: NoticeCommandSuccess,
+				Duration: time.Second * 30,
+				Enabled:  true,
+				Mods:     []string{},
+				MsgID:    "slow_on",
+				Notice:   "notice",
+				VIPs:     []string{},
+			},
+		},
+		{
+			"@msg-id=bad_ban_mod :tmi.twitch.tv NOTICE #mychannel :You cannot ban moderator ModMan unless you are the owner of this channel.",
+			NoticeMessage{
+				Channel:  "#mychannel",
+				IRCType:  "NOTICE",
+				Text:     "You cannot ban moderator ModMan unless you are the owner of this channel.",
+				Type:     NOTICE,
+				Category: NoticePermissionDenied,
+				Mods:     []string{},
+				MsgID:    "bad_ban_mod",
+				Notice:   "notice",
+				Target:   "modman",
+				VIPs:     []string{},
+			},
+		},
+		{
+			"@msg-id=msg_channel_suspended :tmi.twitch.tv NOTICE #gone :This channel does not exist or has been suspended.",
+			NoticeMessage{
+				Channel:  "#gone",
+				IRCType:  "NOTICE",
+				Text:     "This channel does not exist or has been suspended.",
+				Type:     NOTICE,
+				Category: NoticeChannelSuspended,
+				Mods:     []string{},
+				MsgID:    "msg_channel_suspended",
+				Notice:   "notice",
+				VIPs:     []string{},
 			},
 		},
 	}
@@ -631,6 +700,11 @@ func TestParseNoticeMessage(t *testing.T) {
 		assertStringsEqual(t, "MsgID", got.MsgID, test.want.MsgID)
 		assertStringsEqual(t, "Notice", got.Notice, test.want.Notice)
 		assertStringSlicesEqual(t, "VIPs", got.VIPs, test.want.VIPs)
+		assertStringsEqual(t, "Target", got.Target, test.want.Target)
+		assertDurationsEqual(t, "Duration", got.Duration, test.want.Duration)
+		if got.Category != test.want.Category {
+			t.Errorf("Category: got %v, want %v", got.Category, test.want.Category)
+		}
 	}
 }
 
@@ -729,6 +803,39 @@ func TestParseRoomstateMessage(t *testing.T) {
 	}
 }
 
+func TestRoomSettingsMerge(t *testing.T) {
+	var rs RoomSettings
+	var tests = []struct {
+		in   string
+		want RoomSettings
+	}{
+		{
+			"@emote-only=0;followers-only=-1;r9k=0;room-id=12345;slow=0;subs-only=0 :tmi.twitch.tv ROOMSTATE #dallas",
+			RoomSettings{RoomID: "12345"},
+		},
+		{
+			"@room-id=12345;slow=10 :tmi.twitch.tv ROOMSTATE #dallas",
+			RoomSettings{Slow: time.Second * 10, RoomID: "12345"},
+		},
+		{
+			"@followers-only=30;room-id=12345 :tmi.twitch.tv ROOMSTATE #dallas",
+			RoomSettings{FollowersOnly: true, FollowersDuration: time.Minute * 30, Slow: time.Second * 10, RoomID: "12345"},
+		},
+		{
+			"@emote-only=1;room-id=12345;slow=0 :tmi.twitch.tv ROOMSTATE #dallas",
+			RoomSettings{EmoteOnly: true, FollowersOnly: true, FollowersDuration: time.Minute * 30, RoomID: "12345"},
+		},
+	}
+
+	for _, test := range tests {
+		ircData, _ := parseIRCMessage(test.in)
+		rs = rs.Merge(parseRoomstateMessage(ircData))
+		if rs != test.want {
+			t.Errorf("Merge: got %+v, want %+v", rs, test.want)
+		}
+	}
+}
+
 func TestParseUsernoticeMessage(t *testing.T) {
 	tests := []struct {
 		in   string
@@ -783,9 +890,9 @@ func TestParseUsernoticeMessage(t *testing.T) {
 				User: &User{
 					BadgeInfo: "",
 					Badges: []Badge{
-						{"staff", 1},
-						{"broadcaster", 1},
-						{"turbo", 1},
+						{"staff", 1, "1"},
+						{"broadcaster", 1, "1"},
+						{"turbo", 1, "1"},
 					},
 					Broadcaster: true,
 					Color:       "#008000",
@@ -822,8 +929,8 @@ func TestParseUsernoticeMessage(t *testing.T) {
 				User: &User{
 					BadgeInfo: "",
 					Badges: []Badge{
-						{"staff", 1},
-						{"premium", 1},
+						{"staff", 1, "1"},
+						{"premium", 1, "1"},
 					},
 					Broadcaster: false,
 					Color:       "#0000FF",
@@ -860,8 +967,8 @@ func TestParseUsernoticeMessage(t *testing.T) {
 				User: &User{
 					BadgeInfo: "",
 					Badges: []Badge{
-						{"broadcaster", 1},
-						{"subscriber", 6},
+						{"broadcaster", 1, "1"},
+						{"subscriber", 6, "6"},
 					},
 					Broadcaster: true,
 					Color:       "",
@@ -895,7 +1002,7 @@ func TestParseUsernoticeMessage(t *testing.T) {
 				User: &User{
 					BadgeInfo: "",
 					Badges: []Badge{
-						{"turbo", 1},
+						{"turbo", 1, "1"},
 					},
 					Broadcaster: false,
 					Color:       "#9ACD32",
@@ -997,7 +1104,7 @@ func TestParseUserstateMessage(t *testing.T) {
 				User: &User{
 					BadgeInfo: "",
 					Badges: []Badge{
-						{"staff", 1},
+						{"staff", 1, "1"},
 					},
 					Broadcaster: false,
 					Color:       "#0D4200",
@@ -1022,7 +1129,7 @@ func TestParseUserstateMessage(t *testing.T) {
 				User: &User{
 					BadgeInfo: "",
 					Badges: []Badge{
-						{"moderator", 1},
+						{"moderator", 1, "1"},
 					},
 					Broadcaster: false,
 					Color:       "#00FF7F",
@@ -1091,6 +1198,62 @@ func TestParseNamesMessage(t *testing.T) {
 	}
 }
 
+func TestParseEndOfNamesMessage(t *testing.T) {
+	ircData, _ := parseIRCMessage(":you.tmi.twitch.tv 366 you #testchannel :End of /NAMES list")
+	got := parseEndOfNamesMessage(ircData)
+
+	assertStringsEqual(t, "Channel", got.Channel, "#testchannel")
+	assertStringsEqual(t, "IRCType", got.IRCType, "366")
+	assertStringsEqual(t, "Text", got.Text, "End of /NAMES list")
+	assertMessageTypesEqual(t, got.Type, ENDOFNAMES)
+}
+
+func TestParseWelcomeMessage(t *testing.T) {
+	tests := []struct {
+		in   string
+		want WelcomeMessage
+	}{
+		{
+			":tmi.twitch.tv 001 justinfan123 :Welcome, GLHF!",
+			WelcomeMessage{IRCType: "001", Text: "Welcome, GLHF!", Type: WELCOME, Nick: "justinfan123"},
+		},
+		{
+			":tmi.twitch.tv 004 justinfan123 :-",
+			WelcomeMessage{IRCType: "004", Text: "-", Type: WELCOME, Nick: "justinfan123"},
+		},
+	}
+
+	for _, test := range tests {
+		ircData, _ := parseIRCMessage(test.in)
+		got := parseWelcomeMessage(ircData)
+
+		assertStringsEqual(t, "IRCType", got.IRCType, test.want.IRCType)
+		assertStringsEqual(t, "Text", got.Text, test.want.Text)
+		assertMessageTypesEqual(t, got.Type, test.want.Type)
+		assertStringsEqual(t, "Nick", got.Nick, test.want.Nick)
+	}
+}
+
+func TestParseMotdMessage(t *testing.T) {
+	ircData, _ := parseIRCMessage(":tmi.twitch.tv 372 justinfan123 :You are in a maze of twisty passages, all alike.")
+	got := parseMotdMessage(ircData)
+
+	assertStringsEqual(t, "IRCType", got.IRCType, "372")
+	assertStringsEqual(t, "Text", got.Text, "You are in a maze of twisty passages, all alike.")
+	assertMessageTypesEqual(t, got.Type, MOTD)
+	assertStringsEqual(t, "Nick", got.Nick, "justinfan123")
+}
+
+func TestParseUnknownCommandMessage(t *testing.T) {
+	ircData, _ := parseIRCMessage(":tmi.twitch.tv 421 justinfan123 WHO :Unknown command")
+	got := parseUnknownCommandMessage(ircData)
+
+	assertStringsEqual(t, "IRCType", got.IRCType, "421")
+	assertStringsEqual(t, "Text", got.Text, "Unknown command")
+	assertMessageTypesEqual(t, got.Type, UNKNOWNCOMMAND)
+	assertStringsEqual(t, "Command", got.Command, "WHO")
+}
+
 func TestParseJoinMessage(t *testing.T) {
 	tests := []struct {
 		in   string
@@ -1236,10 +1399,14 @@ func TestParsePrivateMessage(t *testing.T) {
 				Emotes:  []Emote{},
 				ID:      "23c201b3-94e9-4d28-8f71-baac329af81c",
 				Reply:   false,
+
+				ClientNonce: "b837cca5074aaa5eb4482e5df50b3e6a",
+				RoomID:      "132230344",
+				SentTime:    time.Unix(1630888435, 197000000),
 				User: &User{
 					BadgeInfo: "",
 					Badges: []Badge{
-						{"premium", 1},
+						{"premium", 1, "1"},
 					},
 					Broadcaster: false,
 					Color:       "",
@@ -1268,11 +1435,15 @@ func TestParsePrivateMessage(t *testing.T) {
 				},
 				ID:    "9d8fca2e-2924-4b50-9655-2e0921d73eb9",
 				Reply: false,
+
+				ClientNonce: "0c57e7357cbea005b349f24ed0bdbf15",
+				RoomID:      "207813352",
+				SentTime:    time.Unix(1630888038, 100000000),
 				User: &User{
 					BadgeInfo: "subscriber/4",
 					Badges: []Badge{
-						{"subscriber", 3},
-						{"glitchcon2020", 1},
+						{"subscriber", 3, "3"},
+						{"glitchcon2020", 1, "1"},
 					},
 					Broadcaster: false,
 					Color:       "#0000FF",
@@ -1299,6 +1470,9 @@ func TestParsePrivateMessage(t *testing.T) {
 				Emotes:  []Emote{},
 				ID:      "6b4dbb8a-b240-42d0-b890-d1f4be18cf10",
 				Reply:   false,
+
+				RoomID:   "62463189",
+				SentTime: time.Unix(1630887934, 441000000),
 				User: &User{
 					BadgeInfo:   "",
 					Badges:      []Badge{},
@@ -1315,13 +1489,60 @@ func TestParsePrivateMessage(t *testing.T) {
 				},
 			},
 		},
+		{
+			"@badge-info=;badges=;color=;custom-reward-id=f8ad8a4c-0c4b-4f63-a6e8-a2b6b9f3e6b0;display-name=Newbie;emotes=;first-msg=1;flags=;id=7a1c;mod=0;msg-id=highlighted-message;returning-chatter=1;room-id=12345;subscriber=0;tmi-sent-ts=1630887934441;turbo=0;user-id=555;user-type= :newbie!newbie@newbie.tmi.twitch.tv PRIVMSG #c :hi everyone",
+			PrivateMessage{
+				Channel: "#c",
+				IRCType: "PRIVMSG",
+				Text:    "hi everyone",
+				Type:    PRIVMSG,
+				Emotes:  []Emote{},
+				ID:      "7a1c",
+
+				CustomRewardID:   "f8ad8a4c-0c4b-4f63-a6e8-a2b6b9f3e6b0",
+				FirstMessage:     true,
+				Highlighted:      true,
+				MsgID:            "highlighted-message",
+				ReturningChatter: true,
+				RoomID:           "12345",
+				SentTime:         time.Unix(1630887934, 441000000),
+				User: &User{
+					Badges:      []Badge{},
+					DisplayName: "Newbie",
+					Name:        "newbie",
+					ID:          "555",
+				},
+			},
+		},
+		{
+			"@badges=;display-name=Payer;emotes=;id=8b2d;msg-id=skip-subs-mode-message;pinned-chat-paid-amount=500;pinned-chat-paid-currency=USD;pinned-chat-paid-exponent=2;pinned-chat-paid-is-system-message=0;pinned-chat-paid-level=TWO;room-id=12345;user-id=556 :payer!payer@payer.tmi.twitch.tv PRIVMSG #c :pinned",
+			PrivateMessage{
+				Channel: "#c",
+				IRCType: "PRIVMSG",
+				Text:    "pinned",
+				Type:    PRIVMSG,
+				Emotes:  []Emote{},
+				ID:      "8b2d",
+
+				HypeChat:     &HypeChat{Amount: 500, Currency: "USD", Exponent: 2, Level: "TWO"},
+				MsgID:        "skip-subs-mode-message",
+				RoomID:       "12345",
+				SkipSubsMode: true,
+				User: &User{
+					Badges:      []Badge{},
+					DisplayName: "Payer",
+					Name:        "payer",
+					ID:          "556",
+				},
+			},
+		},
 	}
 
 	for i := range tests {
 		var test = tests[i]
 
 		ircData, _ := parseIRCMessage(test.in)
-		got := parsePrivateMessage(ircData)
+		got := parsePrivateMessage(ircData, nil)
 
 		assertStringsEqual(t, "Channel", got.Channel, test.want.Channel)
 		assertStringsEqual(t, "IRCType", got.IRCType, test.want.IRCType)
@@ -1333,6 +1554,74 @@ func TestParsePrivateMessage(t *testing.T) {
 		assertStringsEqual(t, "ID", got.ID, test.want.ID)
 		assertBoolsEqual(t, "Reply", got.Reply, test.want.Reply)
 		assertUsersEqual(t, got.User, test.want.User)
+
+		assertStringsEqual(t, "ClientNonce", got.ClientNonce, test.want.ClientNonce)
+		assertStringsEqual(t, "CustomRewardID", got.CustomRewardID, test.want.CustomRewardID)
+		assertBoolsEqual(t, "FirstMessage", got.FirstMessage, test.want.FirstMessage)
+		assertBoolsEqual(t, "Highlighted", got.Highlighted, test.want.Highlighted)
+		assertStringsEqual(t, "MsgID", got.MsgID, test.want.MsgID)
+		assertBoolsEqual(t, "ReturningChatter", got.ReturningChatter, test.want.ReturningChatter)
+		assertStringsEqual(t, "RoomID", got.RoomID, test.want.RoomID)
+		assertBoolsEqual(t, "SkipSubsMode", got.SkipSubsMode, test.want.SkipSubsMode)
+		if !got.SentTime.Equal(test.want.SentTime) {
+			t.Errorf("SentTime: got %v, want %v", got.SentTime, test.want.SentTime)
+		}
+		if (got.HypeChat == nil) != (test.want.HypeChat == nil) ||
+			(got.HypeChat != nil && *got.HypeChat != *test.want.HypeChat) {
+			t.Errorf("HypeChat: got %+v, want %+v", got.HypeChat, test.want.HypeChat)
+		}
+	}
+}
+
+func TestHypeChatValue(t *testing.T) {
+	got := HypeChat{Amount: 150, Currency: "EUR", Exponent: 2}.Value()
+	if got != 1.5 {
+		t.Errorf("Value: got %v, want %v", got, 1.5)
+	}
+}
+
+func TestParseSharedChatSource(t *testing.T) {
+	tests := []struct {
+		in   string
+		want *SharedChatSource
+	}{
+		{"@id=1;room-id=100 :u!u@u.tmi.twitch.tv PRIVMSG #c :hi", nil},
+		{
+			"@id=1;room-id=100;source-badge-info=subscriber/14;source-badges=subscriber/12,premium/1;source-id=2;source-room-id=200 :u!u@u.tmi.twitch.tv PRIVMSG #c :hi",
+			&SharedChatSource{
+				Badges:       []Badge{{"subscriber", 12, "12"}, {"premium", 1, "1"}},
+				BadgeInfos:   []Badge{{"subscriber", 14, "14"}},
+				ID:           "2",
+				OtherChannel: true,
+				RoomID:       "200",
+			},
+		},
+		{
+			"@id=1;room-id=100;source-badge-info=;source-badges=;source-id=1;source-room-id=100 :u!u@u.tmi.twitch.tv PRIVMSG #c :hi",
+			&SharedChatSource{
+				Badges:     []Badge{},
+				BadgeInfos: []Badge{},
+				ID:         "1",
+				RoomID:     "100",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		ircData, _ := parseIRCMessage(test.in)
+		got := parseSharedChatSource(ircData.Tags)
+		if (got == nil) != (test.want == nil) {
+			t.Errorf("Source: got %+v, want %+v", got, test.want)
+			continue
+		}
+		if got == nil {
+			continue
+		}
+		assertStringsEqual(t, "ID", got.ID, test.want.ID)
+		assertStringsEqual(t, "RoomID", got.RoomID, test.want.RoomID)
+		assertBoolsEqual(t, "OtherChannel", got.OtherChannel, test.want.OtherChannel)
+		assertBadgeSlicesEqual(t, "Badges", got.Badges, test.want.Badges)
+		assertBadgeSlicesEqual(t, "BadgeInfos", got.BadgeInfos, test.want.BadgeInfos)
 	}
 }
 
@@ -1401,6 +1690,59 @@ func TestParseWhisperMessage(t *testing.T) {
 	}
 }
 
+func TestParseBadges(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Badge
+	}{
+		{"", []Badge{}},
+		{"subscriber/24,premium/1", []Badge{{"subscriber", 24, "24"}, {"premium", 1, "1"}}},
+		{"predictions/blue-1,vip/1", []Badge{{"predictions", 0, "blue-1"}, {"vip", 1, "1"}}},
+		{"founder/0,bits/1000,broken", []Badge{{"founder", 0, "0"}, {"bits", 1000, "1000"}}},
+	}
+
+	for _, test := range tests {
+		got := parseBadges(test.in)
+		if len(got) != len(test.want) {
+			t.Errorf("%v: len(got) %v, len(want) %v", test.in, len(got), len(test.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%v[%v]: got %v, want %v", test.in, i, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestUserBadgeQueries(t *testing.T) {
+	tests := []struct {
+		in               string
+		subscriberMonths int
+		founder          bool
+		prediction       string
+		hasPrediction    bool
+	}{
+		{"@badge-info=subscriber/24;badges=subscriber/24,premium/1 :u!u@u.tmi.twitch.tv PRIVMSG #c :hi", 24, false, "", false},
+		{"@badge-info=founder/13;badges=founder/0 :u!u@u.tmi.twitch.tv PRIVMSG #c :hi", 13, true, "", false},
+		{`@badge-info=predictions/Team\sBlue;badges=predictions/blue-1 :u!u@u.tmi.twitch.tv PRIVMSG #c :hi`, 0, false, "Team Blue", true},
+		{"@badge-info=;badges=predictions/pink-2 :u!u@u.tmi.twitch.tv PRIVMSG #c :hi", 0, false, "pink-2", true},
+		{"@badge-info=;badges= :u!u@u.tmi.twitch.tv PRIVMSG #c :hi", 0, false, "", false},
+	}
+
+	for _, test := range tests {
+		ircData, _ := parseIRCMessage(test.in)
+		user := parsePrivateMessage(ircData, nil).User
+
+		assertIntsEqual(t, "SubscriberMonths", user.SubscriberMonths(), test.subscriberMonths)
+		assertBoolsEqual(t, "IsFounder", user.IsFounder(), test.founder)
+		prediction, ok := user.PredictionOutcome()
+		assertStringsEqual(t, "PredictionOutcome", prediction, test.prediction)
+		assertBoolsEqual(t, "PredictionOutcome ok", ok, test.hasPrediction)
+		assertBoolsEqual(t, "HasBadge(founder)", user.HasBadge("founder"), test.founder)
+	}
+}
+
 func assertBoolsEqual(t *testing.T, name string, got, want bool) {
 	if got != want {
 		t.Errorf("%v: got %v, want %v", name, got, want)
@@ -1473,14 +1815,7 @@ func assertEmoteSlicesEqual(t *testing.T, got, want []Emote) {
 
 func assertUsersEqual(t *testing.T, got, want *User) {
 	assertStringsEqual(t, "BadgeInfo", got.BadgeInfo, want.BadgeInfo)
-	if len(got.Badges) != len(want.Badges) {
-		t.Errorf("Badges: len(got) %v, len(want) %v", len(got.Badges), len(want.Badges))
-	}
-	for i := range got.Badges {
-		if got.Badges[i] != want.Badges[i] {
-			t.Errorf("Badges[%v]: got %v, want %v", i, got.Badges[i], want.Badges[i])
-		}
-	}
+	assertBadgeSlicesEqual(t, "Badges", got.Badges, want.Badges)
 	assertBoolsEqual(t, "Broadcaster", got.Broadcaster, want.Broadcaster)
 	assertStringsEqual(t, "Color", got.Color, want.Color)
 	assertStringsEqual(t, "DisplayName", got.DisplayName, want.DisplayName)
@@ -1493,6 +1828,18 @@ func assertUsersEqual(t *testing.T, got, want *User) {
 	assertBoolsEqual(t, "VIP", got.VIP, want.VIP)
 }
 
+func assertBadgeSlicesEqual(t *testing.T, name string, got, want []Badge) {
+	if len(got) != len(want) {
+		t.Errorf("%v: len(got) %v, len(want) %v", name, len(got), len(want))
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%v[%v]: got %v, want %v", name, i, got[i], want[i])
+		}
+	}
+}
+
 func assertIRCDataEqual(t *testing.T, got, want *IRCData) {
 	assertStringsEqual(t, "Raw", got.Raw, want.Raw)
 	assertStringMapsEqual(t, "Tags", got.Tags, want.Tags)
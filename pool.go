@@ -0,0 +1,714 @@
+package tmi
+
+import (
+	"errors"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// PoolStrategy for how a Pool picks the connection a channel is joined on.
+type PoolStrategy int
+
+const (
+	// PoolRoundRobin spreads channels evenly over PoolConfig.Size connections in the order they are joined
+	PoolRoundRobin PoolStrategy = iota
+	// PoolHash picks one of PoolConfig.Size connections from a hash of the channel name, so a channel always gets the same one
+	PoolHash
+	// PoolMaxChannels fills connections up to PoolConfig.MaxChannels channels each, adding connections as needed
+	PoolMaxChannels
+)
+
+// PoolConfig holds how a Pool creates its connections and spreads channels over them.
+type PoolConfig struct {
+	Client      ClientConfig // config every connection is created with
+	Strategy    PoolStrategy // how channels are spread over connections
+	Size        int          // number of connections for PoolRoundRobin and PoolHash
+	MaxChannels int          // channels per connection for PoolMaxChannels
+}
+
+// Pool spreads channels over several Clients (connections) that share join and message rate limiters,
+// with the same Join, Part, Say, and callback API as a single Client.
+type Pool struct {
+	assigned      map[string]int // channel to index of the client it is joined on
+	clients       []*Client
+	config        PoolConfig
+	connects      []int // times each client has connected, to rebalance on reconnects
+	done          func(error)
+	errOnce       sync.Once
+	err           error // first error a client's Connect returned
+	handlers      onMessageHandlers
+	handlersMutex sync.RWMutex // guards handlers, which the clients read for every message
+	mutex         sync.Mutex
+	next          int // next client for PoolRoundRobin
+	rLimiterJoins *RateLimiter
+	rLimiterMsgs  *RateLimiter
+	running       bool
+	wg            sync.WaitGroup
+}
+
+// NewPool returns a new pool using the provided config. Size and MaxChannels less than 1 are treated as 1.
+func NewPool(config PoolConfig) *Pool {
+	if config.Size < 1 {
+		config.Size = 1
+	}
+	if config.MaxChannels < 1 {
+		config.MaxChannels = 1
+	}
+	var p = &Pool{
+		assigned: make(map[string]int),
+		config:   config,
+	}
+	var size = config.Size
+	if config.Strategy == PoolMaxChannels {
+		size = 1
+	}
+	for i := 0; i < size; i++ {
+		p.addClient()
+	}
+	return p
+}
+
+// Connect connects all of the pool's clients, and blocks until they are done.
+// It returns the first fatal error from any client, after disconnecting the rest.
+func (p *Pool) Connect() error {
+	p.mutex.Lock()
+	p.running = true
+	p.err = nil
+	p.errOnce = sync.Once{}
+	for _, c := range p.clients {
+		p.start(c)
+	}
+	p.mutex.Unlock()
+
+	p.wg.Wait()
+
+	p.mutex.Lock()
+	p.running = false
+	var err = p.err
+	p.mutex.Unlock()
+
+	if p.done != nil {
+		p.done(err)
+	}
+	return err
+}
+
+// Disconnect disconnects all of the pool's clients.
+func (p *Pool) Disconnect() {
+	p.mutex.Lock()
+	var clients = append([]*Client{}, p.clients...)
+	p.mutex.Unlock()
+	for _, c := range clients {
+		c.Disconnect()
+	}
+}
+
+// Join joins channels, each on the client picked by the pool's strategy.
+func (p *Pool) Join(channels ...string) error {
+	if len(channels) < 1 {
+		return errors.New("channels was empty or nil")
+	}
+
+	var joins = make(map[*Client][]string)
+	p.mutex.Lock()
+	for _, channel := range channels {
+		channel = formatChannel(channel)
+		var i, ok = p.assigned[channel]
+		if !ok {
+			i = p.pick(channel)
+			p.assigned[channel] = i
+		}
+		joins[p.clients[i]] = append(joins[p.clients[i]], channel)
+	}
+	p.mutex.Unlock()
+
+	for c, channels := range joins {
+		c.Join(channels...)
+	}
+	return nil
+}
+
+// Part leaves channels on whichever clients they were joined on.
+func (p *Pool) Part(channels ...string) error {
+	if len(channels) < 1 {
+		return errors.New("channels was empty or nil")
+	}
+
+	var parts = make(map[*Client][]string)
+	p.mutex.Lock()
+	for _, channel := range channels {
+		channel = formatChannel(channel)
+		if i, ok := p.assigned[channel]; ok {
+			parts[p.clients[i]] = append(parts[p.clients[i]], channel)
+			delete(p.assigned, channel)
+		}
+	}
+	p.mutex.Unlock()
+
+	for c, channels := range parts {
+		c.Part(channels...)
+	}
+	return nil
+}
+
+// Say sends a PRIVMSG message in channel on the client channel is joined on.
+// Nothing is sent if channel has not been joined on the pool.
+func (p *Pool) Say(channel string, message string) {
+	if c := p.Client(channel); c != nil {
+		c.Say(channel, message)
+	}
+}
+
+// Action sends a message as a /me, or action, message on the client channel is joined on.
+func (p *Pool) Action(channel, message string) error {
+	var c = p.Client(channel)
+	if c == nil {
+		return errors.New("channel has not been joined on the pool")
+	}
+	return c.Action(channel, message)
+}
+
+// Client returns the client channel is joined on, or nil if channel has not been joined on the pool.
+// Use it for the moderation commands and other Client methods.
+func (p *Pool) Client(channel string) *Client {
+	channel = formatChannel(channel)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var i, ok = p.assigned[channel]
+	if !ok {
+		return nil
+	}
+	return p.clients[i]
+}
+
+// Clients returns the pool's clients.
+func (p *Pool) Clients() []*Client {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]*Client{}, p.clients...)
+}
+
+// Channels returns the status of each channel joined on any of the pool's clients.
+func (p *Pool) Channels() map[string]ChannelStatus {
+	var channels = make(map[string]ChannelStatus)
+	for _, c := range p.Clients() {
+		for channel, status := range c.Channels() {
+			channels[channel] = status
+		}
+	}
+	return channels
+}
+
+// Rebalance reassigns every joined channel by the pool's strategy as if they were all joined now,
+// parting and joining the channels that move to a different client. It is called when a client reconnects.
+func (p *Pool) Rebalance() {
+	var moves = make(map[string][2]*Client)
+
+	p.mutex.Lock()
+	var channels = make([]string, 0, len(p.assigned))
+	for channel := range p.assigned {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	var old = p.assigned
+	p.assigned = make(map[string]int, len(channels))
+	p.next = 0
+	for _, channel := range channels {
+		var i = p.pick(channel)
+		p.assigned[channel] = i
+		if i != old[channel] {
+			moves[channel] = [2]*Client{p.clients[old[channel]], p.clients[i]}
+		}
+	}
+	p.mutex.Unlock()
+
+	for channel, move := range moves {
+		move[0].Part(channel)
+		move[1].Join(channel)
+	}
+}
+
+// SetJoinRateLimit sets one RateLimiter for JOIN commands shared by all of the pool's clients.
+func (p *Pool) SetJoinRateLimit(rl RateLimit) {
+	p.mutex.Lock()
+	p.rLimiterJoins = NewRateLimiter(rl)
+	for _, c := range p.clients {
+		c.rLimiterJoins = p.rLimiterJoins
+	}
+	p.mutex.Unlock()
+}
+
+// SetMessageRateLimit sets one RateLimiter for PRIVMSG lines shared by all of the pool's clients.
+func (p *Pool) SetMessageRateLimit(rl RateLimit) {
+	p.mutex.Lock()
+	p.rLimiterMsgs = NewRateLimiter(rl)
+	for _, c := range p.clients {
+		c.rLimiterMsgs = p.rLimiterMsgs
+	}
+	p.mutex.Unlock()
+}
+
+// UpdatePassword updates the password all of the pool's clients use for authentication.
+func (p *Pool) UpdatePassword(password string) {
+	p.mutex.Lock()
+	p.config.Client.Identity.SetPassword(password)
+	for _, c := range p.clients {
+		c.UpdatePassword(password)
+	}
+	p.mutex.Unlock()
+}
+
+// OnDone sets the callback function for when the pool is done to cb.
+func (p *Pool) OnDone(cb func(fatal error)) {
+	p.done = cb
+}
+
+// addClient creates a client with the pool's config, limiters, and callbacks.
+// addClient requires that the mutex lock is held for the Pool.
+func (p *Pool) addClient() *Client {
+	var c = NewClient(p.config.Client)
+	c.notifDisconnect.reset()
+	c.rLimiterJoins = p.rLimiterJoins
+	c.rLimiterMsgs = p.rLimiterMsgs
+	c.handlers = p.clientHandlers(c)
+	p.clients = append(p.clients, c)
+	p.connects = append(p.connects, 0)
+	if p.running {
+		p.start(c)
+	}
+	return c
+}
+
+// pick returns the index of the client a new channel should be joined on, adding a client if needed.
+// pick requires that the mutex lock is held for the Pool.
+func (p *Pool) pick(channel string) int {
+	switch p.config.Strategy {
+	case PoolHash:
+		var h = fnv.New32a()
+		h.Write([]byte(channel))
+		return int(h.Sum32() % uint32(len(p.clients)))
+
+	case PoolMaxChannels:
+		var counts = make([]int, len(p.clients))
+		for _, i := range p.assigned {
+			counts[i]++
+		}
+		for i, count := range counts {
+			if count < p.config.MaxChannels {
+				return i
+			}
+		}
+		p.addClient()
+		return len(p.clients) - 1
+
+	default:
+		var i = p.next % len(p.clients)
+		p.next++
+		return i
+	}
+}
+
+// start connects c in a goroutine, and disconnects the rest of the pool when it is done.
+// start requires that the mutex lock is held for the Pool.
+func (p *Pool) start(c *Client) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		var err = c.Connect()
+		p.errOnce.Do(func() {
+			p.mutex.Lock()
+			p.err = err
+			p.mutex.Unlock()
+			p.Disconnect()
+		})
+	}()
+}
+
+// a client reconnecting rebalances the pool.
+func (p *Pool) clientConnected(c *Client) {
+	p.mutex.Lock()
+	var reconnect bool
+	for i := range p.clients {
+		if p.clients[i] == c {
+			p.connects[i]++
+			reconnect = p.connects[i] > 1
+		}
+	}
+	p.mutex.Unlock()
+
+	if reconnect {
+		p.Rebalance()
+	}
+}
+
+// loadHandlers returns a copy of the pool's callbacks.
+func (p *Pool) loadHandlers() onMessageHandlers {
+	p.handlersMutex.RLock()
+	defer p.handlersMutex.RUnlock()
+	return p.handlers
+}
+
+// clientHandlers returns callbacks for c that call the pool's current callbacks, for the ones that are set,
+// so replacing a callback after Connect applies to running clients without writing to their handlers.
+// Callbacks that are not set stay nil, so the client skips parsing what nobody handles.
+// onConnected is always set, to rebalance the pool when c reconnects.
+func (p *Pool) clientHandlers(c *Client) onMessageHandlers {
+	var set = p.loadHandlers()
+	var handlers = onMessageHandlers{
+		onConnected: func() {
+			p.clientConnected(c)
+			if cb := p.loadHandlers().onConnected; cb != nil {
+				cb()
+			}
+		},
+	}
+	if set.onUnsetMessage != nil {
+		handlers.onUnsetMessage = func(m UnsetMessage) {
+			if cb := p.loadHandlers().onUnsetMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onCapabilities != nil {
+		handlers.onCapabilities = func(m CapMessage) {
+			if cb := p.loadHandlers().onCapabilities; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onClearChatMessage != nil {
+		handlers.onClearChatMessage = func(m ClearChatMessage) {
+			if cb := p.loadHandlers().onClearChatMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onClearMsgMessage != nil {
+		handlers.onClearMsgMessage = func(m ClearMsgMessage) {
+			if cb := p.loadHandlers().onClearMsgMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onGlobalUserstateMessage != nil {
+		handlers.onGlobalUserstateMessage = func(m GlobalUserstateMessage) {
+			if cb := p.loadHandlers().onGlobalUserstateMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onHostTargetMessage != nil {
+		handlers.onHostTargetMessage = func(m HostTargetMessage) {
+			if cb := p.loadHandlers().onHostTargetMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onNoticeMessage != nil {
+		handlers.onNoticeMessage = func(m NoticeMessage) {
+			if cb := p.loadHandlers().onNoticeMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onReconnectMessage != nil {
+		handlers.onReconnectMessage = func(m ReconnectMessage) {
+			if cb := p.loadHandlers().onReconnectMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onRoomstateMessage != nil {
+		handlers.onRoomstateMessage = func(m RoomstateMessage) {
+			if cb := p.loadHandlers().onRoomstateMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onRoomSettingsChange != nil {
+		handlers.onRoomSettingsChange = func(m RoomSettingsChange) {
+			if cb := p.loadHandlers().onRoomSettingsChange; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onGap != nil {
+		handlers.onGap = func(m Gap) {
+			if cb := p.loadHandlers().onGap; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onUserNoticeMessage != nil {
+		handlers.onUserNoticeMessage = func(m UsernoticeMessage) {
+			if cb := p.loadHandlers().onUserNoticeMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onUserstateMessage != nil {
+		handlers.onUserstateMessage = func(m UserstateMessage) {
+			if cb := p.loadHandlers().onUserstateMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onNamesMessage != nil {
+		handlers.onNamesMessage = func(m NamesMessage) {
+			if cb := p.loadHandlers().onNamesMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onJoinMessage != nil {
+		handlers.onJoinMessage = func(m JoinMessage) {
+			if cb := p.loadHandlers().onJoinMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onPartMessage != nil {
+		handlers.onPartMessage = func(m PartMessage) {
+			if cb := p.loadHandlers().onPartMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onPingMessage != nil {
+		handlers.onPingMessage = func(m PingMessage) {
+			if cb := p.loadHandlers().onPingMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onPongMessage != nil {
+		handlers.onPongMessage = func(m PongMessage) {
+			if cb := p.loadHandlers().onPongMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onPrivateMessage != nil {
+		handlers.onPrivateMessage = func(m PrivateMessage) {
+			if cb := p.loadHandlers().onPrivateMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onFirstMessage != nil {
+		handlers.onFirstMessage = func(m PrivateMessage) {
+			if cb := p.loadHandlers().onFirstMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onChannelPointsRedemption != nil {
+		handlers.onChannelPointsRedemption = func(m PrivateMessage) {
+			if cb := p.loadHandlers().onChannelPointsRedemption; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onHighlightedMessage != nil {
+		handlers.onHighlightedMessage = func(m PrivateMessage) {
+			if cb := p.loadHandlers().onHighlightedMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onWhisperMessage != nil {
+		handlers.onWhisperMessage = func(m WhisperMessage) {
+			if cb := p.loadHandlers().onWhisperMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onWelcomeMessage != nil {
+		handlers.onWelcomeMessage = func(m WelcomeMessage) {
+			if cb := p.loadHandlers().onWelcomeMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onMotdMessage != nil {
+		handlers.onMotdMessage = func(m MotdMessage) {
+			if cb := p.loadHandlers().onMotdMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onEndOfNamesMessage != nil {
+		handlers.onEndOfNamesMessage = func(m EndOfNamesMessage) {
+			if cb := p.loadHandlers().onEndOfNamesMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	if set.onUnknownCommandMessage != nil {
+		handlers.onUnknownCommandMessage = func(m UnknownCommandMessage) {
+			if cb := p.loadHandlers().onUnknownCommandMessage; cb != nil {
+				cb(m)
+			}
+		}
+	}
+	return handlers
+}
+
+// setHandler sets one of the pool's callbacks with set. Before Connect, the clients' callbacks are installed again,
+// so a client only calls the ones that are set. After Connect, a replaced callback applies right away,
+// but one that was not set before only applies to clients added later.
+func (p *Pool) setHandler(set func(*onMessageHandlers)) {
+	p.handlersMutex.Lock()
+	set(&p.handlers)
+	p.handlersMutex.Unlock()
+
+	p.mutex.Lock()
+	if !p.running {
+		for _, c := range p.clients {
+			c.handlers = p.clientHandlers(c)
+		}
+	}
+	p.mutex.Unlock()
+}
+
+// OnUnsetMessage sets the callback for when an unrecognized, non-handled, or unparsable message type is received.
+func (p *Pool) OnUnsetMessage(cb func(UnsetMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onUnsetMessage = cb })
+}
+
+// OnConnected sets the callback for when each of the pool's connections successfully connects.
+func (p *Pool) OnConnected(cb func()) {
+	p.setHandler(func(h *onMessageHandlers) { h.onConnected = cb })
+}
+
+// OnCapabilities sets the callback for when the server replies to the capability request with CAP ACK or NAK.
+func (p *Pool) OnCapabilities(cb func(CapMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onCapabilities = cb })
+}
+
+// OnClearChatMessage sets the callback for when a CLEARCHAT message is received.
+func (p *Pool) OnClearChatMessage(cb func(ClearChatMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onClearChatMessage = cb })
+}
+
+// OnClearMsgMessage sets the callback for when a CLEARMSG message is received.
+func (p *Pool) OnClearMsgMessage(cb func(ClearMsgMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onClearMsgMessage = cb })
+}
+
+// OnGlobalUserstateMessage sets the callback for when a GLOBALUSERSTATE message is received.
+func (p *Pool) OnGlobalUserstateMessage(cb func(GlobalUserstateMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onGlobalUserstateMessage = cb })
+}
+
+// OnHostTargetMessage sets the callback for when a HOSTTARGET message is received.
+func (p *Pool) OnHostTargetMessage(cb func(HostTargetMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onHostTargetMessage = cb })
+}
+
+// OnNoticeMessage sets the callback for when a NOTICE message is received.
+func (p *Pool) OnNoticeMessage(cb func(NoticeMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onNoticeMessage = cb })
+}
+
+// OnReconnectMessage sets the callback for when a RECONNECT message is received.
+func (p *Pool) OnReconnectMessage(cb func(ReconnectMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onReconnectMessage = cb })
+}
+
+// OnRoomstateMessage sets the callback for when a ROOMSTATE message is received.
+func (p *Pool) OnRoomstateMessage(cb func(RoomstateMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onRoomstateMessage = cb })
+}
+
+// OnRoomSettingsChange sets the callback for when a ROOMSTATE message changes a channel's RoomSettings.
+func (p *Pool) OnRoomSettingsChange(cb func(RoomSettingsChange)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onRoomSettingsChange = cb })
+}
+
+// OnGap sets the callback for when a channel is rejoined after a connection was lost.
+func (p *Pool) OnGap(cb func(Gap)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onGap = cb })
+}
+
+// OnUserNoticeMessage sets the callback for when a USERNOTICE message is received.
+func (p *Pool) OnUserNoticeMessage(cb func(UsernoticeMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onUserNoticeMessage = cb })
+}
+
+// OnUserstateMessage sets the callback for when a USERSTATE message is received.
+func (p *Pool) OnUserstateMessage(cb func(UserstateMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onUserstateMessage = cb })
+}
+
+// OnNamesMessage sets the callback for when a 353 message is received.
+func (p *Pool) OnNamesMessage(cb func(NamesMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onNamesMessage = cb })
+}
+
+// OnJoinMessage sets the callback for when a JOIN message is received.
+func (p *Pool) OnJoinMessage(cb func(JoinMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onJoinMessage = cb })
+}
+
+// OnPartMessage sets the callback for when a PART message is received.
+func (p *Pool) OnPartMessage(cb func(PartMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onPartMessage = cb })
+}
+
+// OnPingMessage sets the callback for when a PING message is received.
+func (p *Pool) OnPingMessage(cb func(PingMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onPingMessage = cb })
+}
+
+// OnPongMessage sets the callback for when a PONG message is received.
+func (p *Pool) OnPongMessage(cb func(PongMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onPongMessage = cb })
+}
+
+// OnPrivateMessage sets the callback for when a PRIVMSG message is received.
+func (p *Pool) OnPrivateMessage(cb func(PrivateMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onPrivateMessage = cb })
+}
+
+// OnFirstMessage sets the callback for when a PRIVMSG message is a user's first message in the channel.
+func (p *Pool) OnFirstMessage(cb func(PrivateMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onFirstMessage = cb })
+}
+
+// OnChannelPointsRedemption sets the callback for when a PRIVMSG message is sent by redeeming a custom channel points reward.
+func (p *Pool) OnChannelPointsRedemption(cb func(PrivateMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onChannelPointsRedemption = cb })
+}
+
+// OnHighlightedMessage sets the callback for when a PRIVMSG message is highlighted with channel points.
+func (p *Pool) OnHighlightedMessage(cb func(PrivateMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onHighlightedMessage = cb })
+}
+
+// OnWhisperMessage sets the callback for when a WHISPER message is received.
+func (p *Pool) OnWhisperMessage(cb func(WhisperMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onWhisperMessage = cb })
+}
+
+// OnWelcomeMessage sets the callback for when a 001, 002, 003, or 004 message is received after logging in.
+func (p *Pool) OnWelcomeMessage(cb func(WelcomeMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onWelcomeMessage = cb })
+}
+
+// OnMotdMessage sets the callback for when a 375, 372, or 376 message of the day message is received.
+func (p *Pool) OnMotdMessage(cb func(MotdMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onMotdMessage = cb })
+}
+
+// OnEndOfNamesMessage sets the callback for when a 366 message is received after a channel's NAMES messages.
+func (p *Pool) OnEndOfNamesMessage(cb func(EndOfNamesMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onEndOfNamesMessage = cb })
+}
+
+// OnUnknownCommandMessage sets the callback for when a 421 message is received for a command the server did not recognize.
+func (p *Pool) OnUnknownCommandMessage(cb func(UnknownCommandMessage)) {
+	p.setHandler(func(h *onMessageHandlers) { h.onUnknownCommandMessage = cb })
+}
@@ -0,0 +1,173 @@
+package tmi
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPoolRoundRobin(t *testing.T) {
+	p := NewPool(PoolConfig{Client: NewClientConfig("", ""), Strategy: PoolRoundRobin, Size: 2})
+	p.Join("a", "b", "c")
+
+	clients := p.Clients()
+	assertIntsEqual(t, "len(Clients())", len(clients), 2)
+	if p.Client("a") != clients[0] || p.Client("b") != clients[1] || p.Client("c") != clients[0] {
+		t.Errorf("channels were not joined round robin")
+	}
+	assertIntsEqual(t, "len(Channels())", len(p.Channels()), 3)
+	assertIntsEqual(t, "client 0 channels", len(clients[0].Channels()), 2)
+	assertIntsEqual(t, "client 1 channels", len(clients[1].Channels()), 1)
+	if p.Client("z") != nil {
+		t.Errorf("Client should be nil for a channel that has not been joined")
+	}
+	if err := p.Action("z", "hi"); err == nil {
+		t.Errorf("expected an error for an action in a channel that has not been joined")
+	}
+
+	p.Part("a")
+	if _, ok := p.Channels()["#a"]; ok {
+		t.Errorf("#a should have been parted")
+	}
+}
+
+func TestPoolHash(t *testing.T) {
+	p := NewPool(PoolConfig{Client: NewClientConfig("", ""), Strategy: PoolHash, Size: 4})
+	p.Join("a", "b", "c", "d", "e")
+
+	q := NewPool(PoolConfig{Client: NewClientConfig("", ""), Strategy: PoolHash, Size: 4})
+	q.Join("e", "d", "c", "b", "a")
+
+	pClients, qClients := p.Clients(), q.Clients()
+	for _, channel := range []string{"a", "b", "c", "d", "e"} {
+		var pi, qi int
+		for i := range pClients {
+			if p.Client(channel) == pClients[i] {
+				pi = i
+			}
+			if q.Client(channel) == qClients[i] {
+				qi = i
+			}
+		}
+		if pi != qi {
+			t.Errorf("%v: hashed to client %v and %v", channel, pi, qi)
+		}
+	}
+}
+
+func TestPoolMaxChannels(t *testing.T) {
+	p := NewPool(PoolConfig{Client: NewClientConfig("", ""), Strategy: PoolMaxChannels, MaxChannels: 2})
+	assertIntsEqual(t, "len(Clients()) before join", len(p.Clients()), 1)
+
+	p.Join("a", "b", "c", "d", "e")
+	clients := p.Clients()
+	assertIntsEqual(t, "len(Clients())", len(clients), 3)
+	for i, want := range []int{2, 2, 1} {
+		assertIntsEqual(t, "client channels", len(clients[i].Channels()), want)
+	}
+
+	p.Part("a")
+	p.Join("f")
+	if p.Client("f") != clients[0] {
+		t.Errorf("f should fill the space a left on the first client")
+	}
+}
+
+func TestPoolSay(t *testing.T) {
+	p := NewPool(PoolConfig{Client: NewClientConfig("", ""), Strategy: PoolRoundRobin, Size: 2})
+	clients := p.Clients()
+	p.Join("a", "b")
+
+	p.Say("b", "hello")
+	select {
+	case got := <-clients[1].outbound:
+		assertStringsEqual(t, "Say", got, "PRIVMSG #b :hello")
+	default:
+		t.Errorf("Say was not sent on the client #b was joined on")
+	}
+	if len(clients[0].outbound) != 0 {
+		t.Errorf("Say was sent on the wrong client")
+	}
+}
+
+func TestPoolRebalance(t *testing.T) {
+	p := NewPool(PoolConfig{Client: NewClientConfig("", ""), Strategy: PoolRoundRobin, Size: 2})
+	clients := p.Clients()
+	p.Join("c", "b", "a")
+	if p.Client("c") != clients[0] {
+		t.Fatalf("c should have been joined on the first client")
+	}
+
+	p.Rebalance()
+	for channel, want := range map[string]*Client{"a": clients[0], "b": clients[1], "c": clients[0]} {
+		if p.Client(channel) != want {
+			t.Errorf("%v was not rebalanced to the expected client", channel)
+		}
+	}
+	if _, ok := clients[1].Channels()["#c"]; ok {
+		t.Errorf("#c should have been parted on the second client")
+	}
+	if _, ok := clients[1].Channels()["#b"]; !ok {
+		t.Errorf("#b should have been joined on the second client")
+	}
+}
+
+func TestPoolRebalanceOnReconnect(t *testing.T) {
+	p := NewPool(PoolConfig{Client: NewClientConfig("", ""), Strategy: PoolRoundRobin, Size: 2})
+	clients := p.Clients()
+	p.Join("c", "a", "b")
+
+	clients[1].handlers.onConnected() // first connect
+	if p.Client("c") != clients[0] || p.Client("a") != clients[1] || p.Client("b") != clients[0] {
+		t.Fatalf("the first connect should not move channels")
+	}
+	clients[0].handlers.onConnected()
+	clients[0].handlers.onConnected() // reconnect
+	if p.Client("a") != clients[0] || p.Client("b") != clients[1] || p.Client("c") != clients[0] {
+		t.Errorf("channels were not redistributed after a reconnect")
+	}
+	if _, ok := clients[1].Channels()["#b"]; !ok {
+		t.Errorf("#b should have been joined on the second client")
+	}
+	if _, ok := clients[1].Channels()["#a"]; ok {
+		t.Errorf("#a should have been parted on the second client")
+	}
+}
+
+func TestPoolHandlers(t *testing.T) {
+	p := NewPool(PoolConfig{Client: NewClientConfig("", ""), Strategy: PoolMaxChannels, MaxChannels: 1})
+	var received int
+	p.OnPrivateMessage(func(m PrivateMessage) { received++ })
+	p.Join("a", "b")
+
+	for _, c := range p.Clients() {
+		c.handleIRCMessage(":user!user@user.tmi.twitch.tv PRIVMSG #a :hi")
+	}
+	assertIntsEqual(t, "received", received, 2)
+	for _, c := range p.Clients() {
+		if c.handlers.onClearChatMessage != nil {
+			t.Errorf("callbacks the pool does not set should stay nil on its clients")
+		}
+	}
+
+	var replaced int
+	p.OnPrivateMessage(func(m PrivateMessage) { replaced++ })
+	p.Clients()[0].handleIRCMessage(":user!user@user.tmi.twitch.tv PRIVMSG #a :hi")
+	assertIntsEqual(t, "received", received, 2)
+	assertIntsEqual(t, "received by the replaced callback", replaced, 1)
+}
+
+func TestPoolSharedRateLimit(t *testing.T) {
+	p := NewPool(PoolConfig{Client: NewClientConfig("", ""), Strategy: PoolRoundRobin, Size: 2})
+	p.SetMessageRateLimit(RateLimit{Burst: 1, Rate: time.Hour})
+	clients := p.Clients()
+	if clients[0].rLimiterMsgs != clients[1].rLimiterMsgs {
+		t.Fatalf("clients should share one message rate limiter")
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	assertBoolsEqual(t, "first PRIVMSG", clients[0].waitMessageLimit(ctx, "PRIVMSG #a :hi"), true)
+	assertBoolsEqual(t, "JOIN", clients[1].waitMessageLimit(ctx, "JOIN #a"), true)
+	assertBoolsEqual(t, "second PRIVMSG", clients[1].waitMessageLimit(ctx, "PRIVMSG #b :hi"), false)
+}
@@ -0,0 +1,199 @@
+package tmi
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Chatter is a user the presence tracker counts as in a channel's chat.
+type Chatter struct {
+	Channel  string    `json:"channel"`
+	Username string    `json:"username"`
+	LastSeen time.Time `json:"last-seen"` // last JOIN, NAMES, or PRIVMSG from the user
+}
+
+// Presence tracks who is in each joined channel's chat from NAMES lists, JOIN and PART messages, and chatters seen in PRIVMSG messages.
+// JOIN, PART, and NAMES messages require the CapMembership capability.
+type Presence struct {
+	channels map[string]map[string]time.Time // channel to username to last seen
+	idle     time.Duration
+	mutex    sync.Mutex
+	names    map[string][]string // channel to usernames from 353 messages, until the 366 message
+	onArrive func(Chatter)
+	onLeave  func(Chatter)
+}
+
+// TrackPresence starts tracking who is in chat for each joined channel, and returns the tracker.
+// Chatters not seen for idle are counted as having left, an idle of 0 or less never expires chatters.
+// TrackPresence should be called before Connect.
+func (c *Client) TrackPresence(idle time.Duration) *Presence {
+	c.presence = &Presence{
+		channels: make(map[string]map[string]time.Time),
+		idle:     idle,
+		names:    make(map[string][]string),
+	}
+	return c.presence
+}
+
+// Chatters returns the sorted usernames in channel's chat.
+func (p *Presence) Chatters(channel string) []string {
+	channel = formatChannel(channel)
+	p.mutex.Lock()
+	var chatters = make([]string, 0, len(p.channels[channel]))
+	for username := range p.channels[channel] {
+		chatters = append(chatters, username)
+	}
+	p.mutex.Unlock()
+	sort.Strings(chatters)
+	return chatters
+}
+
+// Count returns the number of chatters in channel's chat.
+func (p *Presence) Count(channel string) int {
+	channel = formatChannel(channel)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return len(p.channels[channel])
+}
+
+// LastSeen returns when username was last seen in channel, and false if they are not in its chat.
+func (p *Presence) LastSeen(channel, username string) (time.Time, bool) {
+	channel = formatChannel(channel)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var lastSeen, ok = p.channels[channel][strings.ToLower(username)]
+	return lastSeen, ok
+}
+
+// OnArrive sets the callback for when a chatter arrives in a channel's chat.
+func (p *Presence) OnArrive(cb func(Chatter)) {
+	p.mutex.Lock()
+	p.onArrive = cb
+	p.mutex.Unlock()
+}
+
+// OnLeave sets the callback for when a chatter parts a channel or has been idle for longer than the tracker's idle time.
+func (p *Presence) OnLeave(cb func(Chatter)) {
+	p.mutex.Lock()
+	p.onLeave = cb
+	p.mutex.Unlock()
+}
+
+// seen marks username as in channel's chat at t, calling onArrive if they were not.
+func (p *Presence) seen(channel, username string, t time.Time) {
+	if username == "" {
+		return
+	}
+	username = strings.ToLower(username)
+	p.mutex.Lock()
+	var chatters, ok = p.channels[channel]
+	if !ok {
+		chatters = make(map[string]time.Time)
+		p.channels[channel] = chatters
+	}
+	var _, present = chatters[username]
+	chatters[username] = t
+	var onArrive = p.onArrive
+	p.mutex.Unlock()
+
+	if !present && onArrive != nil {
+		onArrive(Chatter{Channel: channel, Username: username, LastSeen: t})
+	}
+}
+
+// left removes username from channel's chat, calling onLeave if they were in it.
+func (p *Presence) left(channel, username string) {
+	username = strings.ToLower(username)
+	p.mutex.Lock()
+	var lastSeen, present = p.channels[channel][username]
+	delete(p.channels[channel], username)
+	var onLeave = p.onLeave
+	p.mutex.Unlock()
+
+	if present && onLeave != nil {
+		onLeave(Chatter{Channel: channel, Username: username, LastSeen: lastSeen})
+	}
+}
+
+// forget drops everything tracked for channel without calling onLeave, for when the client parts it.
+func (p *Presence) forget(channel string) {
+	p.mutex.Lock()
+	delete(p.channels, channel)
+	delete(p.names, channel)
+	p.mutex.Unlock()
+}
+
+// addNames collects a 353 message's usernames until the channel's 366 message.
+func (p *Presence) addNames(m NamesMessage) {
+	p.mutex.Lock()
+	p.names[m.Channel] = append(p.names[m.Channel], m.Users...)
+	p.mutex.Unlock()
+}
+
+// endNames merges the usernames collected from 353 messages into channel's chat.
+func (p *Presence) endNames(channel string, t time.Time) {
+	p.mutex.Lock()
+	var names = p.names[channel]
+	delete(p.names, channel)
+	p.mutex.Unlock()
+
+	for _, username := range names {
+		p.seen(channel, username, t)
+	}
+}
+
+// expire removes chatters last seen more than the idle time before now, calling onLeave for each.
+func (p *Presence) expire(now time.Time) {
+	if p.idle <= 0 {
+		return
+	}
+	var expired []Chatter
+	p.mutex.Lock()
+	for channel, chatters := range p.channels {
+		for username, lastSeen := range chatters {
+			if now.Sub(lastSeen) > p.idle {
+				delete(chatters, username)
+				expired = append(expired, Chatter{Channel: channel, Username: username, LastSeen: lastSeen})
+			}
+		}
+	}
+	var onLeave = p.onLeave
+	p.mutex.Unlock()
+
+	if onLeave != nil {
+		for _, chatter := range expired {
+			onLeave(chatter)
+		}
+	}
+}
+
+// spawnPresenceExpiry expires idle chatters a few times per idle period while connected.
+func (c *Client) spawnPresenceExpiry(ctx context.Context, wg *sync.WaitGroup) {
+	var presence = c.presence
+	if presence == nil || presence.idle <= 0 {
+		return
+	}
+	var interval = presence.idle / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		var ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				presence.expire(now)
+			}
+		}
+	}()
+}
@@ -0,0 +1,70 @@
+package tmi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPresence(t *testing.T) {
+	c := NewClient(NewClientConfig("me", "oauth:token"))
+	p := c.TrackPresence(time.Minute)
+
+	var arrived, left []string
+	p.OnArrive(func(ch Chatter) { arrived = append(arrived, ch.Username) })
+	p.OnLeave(func(ch Chatter) { left = append(left, ch.Username) })
+
+	for _, raw := range []string{
+		":me.tmi.twitch.tv 353 me = #a :alice bob",
+		":me.tmi.twitch.tv 353 me = #a :carol",
+		":me.tmi.twitch.tv 366 me #a :End of /NAMES list",
+		":dave!dave@dave.tmi.twitch.tv JOIN #a",
+		":bob!bob@bob.tmi.twitch.tv PART #a",
+		"@display-name=Erin :erin!erin@erin.tmi.twitch.tv PRIVMSG #a :hi",
+		":alice!alice@alice.tmi.twitch.tv PRIVMSG #a :hi again",
+		"@room-id=1;source-room-id=2 :frank!frank@frank.tmi.twitch.tv PRIVMSG #a :hi from a partner channel",
+	} {
+		if err := c.handleIRCMessage(raw); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertStringSlicesEqual(t, "arrived", arrived, []string{"alice", "bob", "carol", "dave", "erin"})
+	assertStringSlicesEqual(t, "left", left, []string{"bob"})
+	assertStringSlicesEqual(t, "Chatters", p.Chatters("a"), []string{"alice", "carol", "dave", "erin"})
+	assertIntsEqual(t, "Count", p.Count("#a"), 4)
+	if _, ok := p.LastSeen("a", "Erin"); !ok {
+		t.Errorf("LastSeen: erin should be in chat")
+	}
+	if _, ok := p.LastSeen("a", "bob"); ok {
+		t.Errorf("LastSeen: bob should have left")
+	}
+
+	// alice spoke, so only the others expire
+	aliceSeen, _ := p.LastSeen("a", "alice")
+	p.mutex.Lock()
+	for username := range p.channels["#a"] {
+		if username != "alice" {
+			p.channels["#a"][username] = aliceSeen.Add(-time.Minute)
+		}
+	}
+	p.mutex.Unlock()
+	left = nil
+	p.expire(aliceSeen.Add(time.Second))
+	assertIntsEqual(t, "expired", len(left), 3)
+	assertStringSlicesEqual(t, "Chatters after expiry", p.Chatters("a"), []string{"alice"})
+
+	// the client's own PART forgets the channel without leave events
+	left = nil
+	c.handleIRCMessage(":me!me@me.tmi.twitch.tv PART #a")
+	assertIntsEqual(t, "Count after own part", p.Count("a"), 0)
+	assertIntsEqual(t, "left after own part", len(left), 0)
+
+	// a lost connection forgets joined channels until they are resynced from NAMES
+	c.Join("b")
+	c.handleIRCMessage("@emote-only=0;room-id=3 :tmi.twitch.tv ROOMSTATE #b")
+	c.handleIRCMessage(":gina!gina@gina.tmi.twitch.tv PRIVMSG #b :hi")
+	assertIntsEqual(t, "Count before gap", p.Count("b"), 1)
+	c.markGaps(time.Now())
+	assertIntsEqual(t, "Count after gap", p.Count("b"), 0)
+	assertIntsEqual(t, "left after gap", len(left), 0)
+}
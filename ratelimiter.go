@@ -1,6 +1,7 @@
 package tmi
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -43,6 +44,9 @@ var (
 	// RLimWhisperDefault is the rate limit for any account of 100 messages per 60s
 	// 100 / minute is more constricting than 3 / second, so it is chosen
 	RLimWhisperDefault = RateLimit{Burst: 2, Rate: time.Minute / 100}
+
+	// RLimHelixDefault is the Helix API rate limit 800 requests per 60s for each client ID and user
+	RLimHelixDefault = RateLimit{Burst: 400, Rate: time.Minute / 800}
 )
 
 // NewRateLimiter returns a new RateLimiter based on the RateLimit provided.
@@ -60,7 +64,18 @@ func NewRateLimiter(rl RateLimit) *RateLimiter {
 // the refill rate to determine how long to wait before a token becomes available.
 // Wait is thread safe.
 func (rl *RateLimiter) Wait() {
+	var wait = rl.take()
+	if wait > 0 {
+		t := time.NewTimer(wait)
+		<-t.C
+	}
+}
+
+// take claims a token like Wait, but returns how long to wait instead of waiting,
+// so the caller can do something else first.
+func (rl *RateLimiter) take() time.Duration {
 	rl.mu.Lock()
+	defer rl.mu.Unlock()
 
 	rl.replenish()
 
@@ -69,12 +84,22 @@ func (rl *RateLimiter) Wait() {
 	if rl.tokens < 0 {
 		wait = time.Duration((-rl.tokens / rl.rate) * float64(time.Second))
 	}
+	return wait
+}
 
-	rl.mu.Unlock()
-
-	if wait > 0 {
-		t := time.NewTimer(wait)
-		<-t.C
+// waitContext waits like Wait, and returns false if ctx is done before a token becomes available.
+func (rl *RateLimiter) waitContext(ctx context.Context) bool {
+	var wait = rl.take()
+	if wait <= 0 {
+		return true
+	}
+	var t = time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
 	}
 }
 
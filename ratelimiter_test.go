@@ -75,3 +75,12 @@ func TestMultiRoutineRateLimiterWait(t *testing.T) {
 		t.Errorf("got %v, want %v", diff, want)
 	}
 }
+
+func TestRateLimiterTake(t *testing.T) {
+	rl := NewRateLimiter(RateLimit{Burst: 2, Rate: time.Second})
+	assertDurationsEqual(t, "first take", rl.take(), 0)
+	assertDurationsEqual(t, "second take", rl.take(), 0)
+	if wait := rl.take(); wait <= 0 || wait > time.Second {
+		t.Errorf("third take: got %v, want (0, 1s]", wait)
+	}
+}
@@ -0,0 +1,100 @@
+package tmi
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNotModerator is returned by moderation commands, or passed to OnModeratorError by the ones that do not return an error,
+// when the client's own USERSTATE for the channel shows it is neither a moderator nor the broadcaster.
+var ErrNotModerator = errors.New("not a moderator in channel")
+
+// Self is the client's own user state, from GLOBALUSERSTATE and USERSTATE messages.
+type Self struct {
+	Channel   string   `json:"channel"`    // empty for the global state
+	EmoteSets []string `json:"emote-sets"` // emote sets the user can use
+	User      User     `json:"user"`       // ID, display name, color, and badges of the user
+}
+
+// Moderator reports whether the user is a moderator or the broadcaster of the channel.
+func (s Self) Moderator() bool {
+	return s.User.Mod || s.User.Broadcaster
+}
+
+// Me returns the client's own user state from the last GLOBALUSERSTATE message, and false if none has been received.
+func (c *Client) Me() (Self, bool) {
+	c.selfMutex.Lock()
+	defer c.selfMutex.Unlock()
+	if c.me == nil {
+		return Self{}, false
+	}
+	return *c.me, true
+}
+
+// MeIn returns the client's own user state in channel from the last USERSTATE message,
+// and false if none has been received for channel since joining it.
+func (c *Client) MeIn(channel string) (Self, bool) {
+	channel = formatChannel(channel)
+	c.selfMutex.Lock()
+	defer c.selfMutex.Unlock()
+	var self, ok = c.meIn[channel]
+	return self, ok
+}
+
+// checkModerator returns ErrNotModerator if the client is known not to be a moderator in channel.
+// It returns nil when no USERSTATE has been received for channel yet.
+func (c *Client) checkModerator(channel string) error {
+	if self, ok := c.MeIn(channel); ok && !self.Moderator() {
+		return ErrNotModerator
+	}
+	return nil
+}
+
+// isSelf reports whether a user is the client's own user, by ID once known and by login otherwise.
+func (c *Client) isSelf(u *User) bool {
+	if u == nil {
+		return false
+	}
+	c.selfMutex.Lock()
+	defer c.selfMutex.Unlock()
+	if c.me != nil && c.me.User.ID != "" && u.ID != "" {
+		return u.ID == c.me.User.ID
+	}
+	return c.echoLogin != "" && strings.EqualFold(u.Name, c.echoLogin)
+}
+
+// updateSelf keeps the client's own global state.
+func (c *Client) updateSelf(m GlobalUserstateMessage) {
+	var self = Self{EmoteSets: m.EmoteSets}
+	if m.User != nil {
+		self.User = *m.User
+	}
+	self.User.Name = strings.ToLower(c.config.Identity.Username)
+
+	c.selfMutex.Lock()
+	c.me = &self
+	c.selfMutex.Unlock()
+}
+
+// updateSelfIn keeps the client's own state in a channel, with the ID from the global state.
+func (c *Client) updateSelfIn(m UserstateMessage) {
+	var self = Self{Channel: m.Channel, EmoteSets: m.EmoteSets}
+	if m.User != nil {
+		self.User = *m.User
+	}
+	self.User.Name = strings.ToLower(c.config.Identity.Username)
+
+	c.selfMutex.Lock()
+	if c.me != nil && self.User.ID == "" {
+		self.User.ID = c.me.User.ID
+	}
+	c.meIn[m.Channel] = self
+	c.selfMutex.Unlock()
+}
+
+// forgetSelfIn drops the client's own state in channel, for when the client parts it.
+func (c *Client) forgetSelfIn(channel string) {
+	c.selfMutex.Lock()
+	delete(c.meIn, channel)
+	c.selfMutex.Unlock()
+}
@@ -0,0 +1,78 @@
+package tmi
+
+import "testing"
+
+func TestSelfState(t *testing.T) {
+	c := NewClient(NewClientConfig("Me", "oauth:token"))
+	if _, ok := c.Me(); ok {
+		t.Errorf("Me should not be known before GLOBALUSERSTATE")
+	}
+
+	c.handleIRCMessage("@badge-info=;badges=turbo/1;color=#0D4200;display-name=Me;emote-sets=0,33,50;turbo=1;user-id=1337;user-type= :tmi.twitch.tv GLOBALUSERSTATE")
+	c.handleIRCMessage("@badge-info=;badges=moderator/1;color=#0D4200;display-name=Me;emote-sets=0,33;mod=1;subscriber=0;user-type=mod :tmi.twitch.tv USERSTATE #modded")
+	c.handleIRCMessage("@badge-info=;badges=;color=#0D4200;display-name=Me;emote-sets=0;mod=0;subscriber=0;user-type= :tmi.twitch.tv USERSTATE #plain")
+
+	me, ok := c.Me()
+	assertBoolsEqual(t, "Me ok", ok, true)
+	assertStringsEqual(t, "ID", me.User.ID, "1337")
+	assertStringsEqual(t, "Name", me.User.Name, "me")
+	assertStringsEqual(t, "DisplayName", me.User.DisplayName, "Me")
+	assertStringsEqual(t, "Color", me.User.Color, "#0D4200")
+	assertStringSlicesEqual(t, "EmoteSets", me.EmoteSets, []string{"0", "33", "50"})
+
+	modded, ok := c.MeIn("Modded")
+	assertBoolsEqual(t, "MeIn ok", ok, true)
+	assertStringsEqual(t, "MeIn ID", modded.User.ID, "1337")
+	assertBoolsEqual(t, "modded Moderator", modded.Moderator(), true)
+	plain, _ := c.MeIn("plain")
+	assertBoolsEqual(t, "plain Moderator", plain.Moderator(), false)
+
+	if err := c.Ban("plain", "someone", ""); err != ErrNotModerator {
+		t.Errorf("Ban in #plain: got %v, want %v", err, ErrNotModerator)
+	}
+	if err := c.Ban("modded", "someone", ""); err != nil {
+		t.Errorf("Ban in #modded: got %v, want nil", err)
+	}
+	if err := c.Ban("unknown", "someone", ""); err != nil {
+		t.Errorf("Ban in #unknown: got %v, want nil", err)
+	}
+
+	var modErrs []error
+	c.OnModeratorError(func(err error) { modErrs = append(modErrs, err) })
+	var sent = len(c.outbound)
+	c.Unban("plain", "someone")
+	c.SlowOff("plain")
+	assertIntsEqual(t, "lines sent in #plain", len(c.outbound), sent)
+	assertIntsEqual(t, "moderator errors", len(modErrs), 2)
+	for _, err := range modErrs {
+		if err != ErrNotModerator {
+			t.Errorf("got moderator error %v, want %v", err, ErrNotModerator)
+		}
+	}
+
+	c.Part("plain")
+	if _, ok := c.MeIn("plain"); ok {
+		t.Errorf("MeIn should be forgotten after parting")
+	}
+}
+
+func TestIgnoreSelf(t *testing.T) {
+	config := NewClientConfig("me", "oauth:token")
+	config.IgnoreSelf = true
+	c := NewClient(config)
+
+	var received []string
+	c.OnPrivateMessage(func(m PrivateMessage) { received = append(received, m.Text) })
+
+	c.handleIRCMessage("@display-name=Me;user-id=1337 :me!me@me.tmi.twitch.tv PRIVMSG #a :by login")
+	c.handleIRCMessage("@user-id=1337 :tmi.twitch.tv GLOBALUSERSTATE")
+	c.handleIRCMessage("@display-name=Me;user-id=1337 :me!me@me.tmi.twitch.tv PRIVMSG #a :by id")
+	c.handleIRCMessage("@display-name=Other;user-id=42 :other!other@other.tmi.twitch.tv PRIVMSG #a :other")
+
+	assertStringSlicesEqual(t, "received", received, []string{"other"})
+
+	d := NewDualClient(config)
+	d.OnPrivateMessage(func(m PrivateMessage) { received = append(received, m.Text) })
+	d.Reader().handleIRCMessage("@display-name=Me;user-id=1337 :me!me@me.tmi.twitch.tv PRIVMSG #a :writer echo")
+	assertStringSlicesEqual(t, "received by reader", received, []string{"other"})
+}
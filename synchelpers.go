@@ -24,18 +24,23 @@ type connCloseErr struct {
 }
 
 func (t *connCloseErr) update(err error) {
-	var override = err == ErrDisconnectCalled || err == ErrLoginFailure
+	var override = isFatal(err)
 	t.mutex.Lock()
 	if t.err == nil {
 		t.err = err
 	} else if override {
-		if t.err != ErrDisconnectCalled && t.err != ErrLoginFailure {
+		if !isFatal(t.err) {
 			t.err = err
 		}
 	}
 	t.mutex.Unlock()
 }
 
+// isFatal reports whether err should stop the client from reconnecting.
+func isFatal(err error) bool {
+	return err == ErrDisconnectCalled || err == ErrLoginFailure || err == ErrCapabilitiesDenied
+}
+
 // notifier's reset() and notify() methods are used in combination to notify multiple goroutines to close.
 // call reset() before spawning goroutines
 // call notify() in any goroutines to signal one another by listening to the notifier's channel ch
@@ -0,0 +1,175 @@
+package tmi
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UserRecord is what the user directory knows about a user.
+type UserRecord struct {
+	ID          string             `json:"id"`
+	Login       string             `json:"login"`
+	DisplayName string             `json:"display-name"`
+	Color       string             `json:"color"`
+	Badges      map[string][]Badge `json:"badges"`       // channel to the user's badges in it
+	LastChannel string             `json:"last-channel"` // channel of the user's last message, empty for whispers
+	LastSeen    time.Time          `json:"last-seen"`
+}
+
+// UserRename when a user ID is seen with a different login or display name than before.
+type UserRename struct {
+	ID             string `json:"id"`
+	OldLogin       string `json:"old-login"`
+	NewLogin       string `json:"new-login"`
+	OldDisplayName string `json:"old-display-name"`
+	NewDisplayName string `json:"new-display-name"`
+}
+
+// UserDirectory keeps the most recently active users from every message with a user-id tag, evicting the least recently active.
+type UserDirectory struct {
+	byID     map[string]*list.Element // user ID to element of lru holding a *UserRecord
+	byLogin  map[string]string        // login to user ID
+	capacity int
+	lru      *list.List // most recently active at the front
+	mutex    sync.Mutex
+	onRename func(UserRename)
+}
+
+// TrackUsers starts keeping up to capacity users, and returns the directory.
+// Capacity less than 1 is treated as 1. TrackUsers should be called before Connect.
+func (c *Client) TrackUsers(capacity int) *UserDirectory {
+	if capacity < 1 {
+		capacity = 1
+	}
+	c.users = &UserDirectory{
+		byID:     make(map[string]*list.Element),
+		byLogin:  make(map[string]string),
+		capacity: capacity,
+		lru:      list.New(),
+	}
+	return c.users
+}
+
+// ByID returns the user with id, and false if the user is not in the directory.
+func (d *UserDirectory) ByID(id string) (UserRecord, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	var e, ok = d.byID[id]
+	if !ok {
+		return UserRecord{}, false
+	}
+	return e.Value.(*UserRecord).copy(), true
+}
+
+// ByLogin returns the user with login, and false if the user is not in the directory.
+func (d *UserDirectory) ByLogin(login string) (UserRecord, bool) {
+	d.mutex.Lock()
+	var id, ok = d.byLogin[strings.ToLower(login)]
+	d.mutex.Unlock()
+	if !ok {
+		return UserRecord{}, false
+	}
+	return d.ByID(id)
+}
+
+// Len returns the number of users in the directory.
+func (d *UserDirectory) Len() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.lru.Len()
+}
+
+// OnRename sets the callback for when a user ID is seen with a different login or display name than before.
+func (d *UserDirectory) OnRename(cb func(UserRename)) {
+	d.mutex.Lock()
+	d.onRename = cb
+	d.mutex.Unlock()
+}
+
+// observe updates the directory from a message with a user-id tag, and a login from its login tag or prefix.
+func (d *UserDirectory) observe(data IRCData, t time.Time) {
+	var id = data.Tags["user-id"]
+	var login = data.Tags["login"]
+	if login == "" {
+		login = parseUsernameFromPrefix(data.Prefix)
+	}
+	if id == "" || login == "" {
+		return
+	}
+	login = strings.ToLower(login)
+	var channel string
+	if len(data.Params) > 0 && strings.HasPrefix(data.Params[0], "#") {
+		channel = data.Params[0]
+	}
+
+	var displayName, color = data.Tags["display-name"], data.Tags["color"]
+
+	d.mutex.Lock()
+	var rename *UserRename
+	var user *UserRecord
+	if e, ok := d.byID[id]; ok {
+		d.lru.MoveToFront(e)
+		user = e.Value.(*UserRecord)
+		if displayName == "" { // not every message has the tag
+			displayName = user.DisplayName
+		}
+		if color == "" {
+			color = user.Color
+		}
+		if user.Login != login || user.DisplayName != displayName {
+			rename = &UserRename{
+				ID:             id,
+				OldLogin:       user.Login,
+				NewLogin:       login,
+				OldDisplayName: user.DisplayName,
+				NewDisplayName: displayName,
+			}
+			if d.byLogin[user.Login] == id {
+				delete(d.byLogin, user.Login)
+			}
+		}
+	} else {
+		user = &UserRecord{ID: id, Badges: make(map[string][]Badge)}
+		d.byID[id] = d.lru.PushFront(user)
+		if d.lru.Len() > d.capacity {
+			d.evict(d.lru.Back())
+		}
+	}
+	user.Login = login
+	user.DisplayName = displayName
+	user.Color = color
+	user.LastChannel = channel
+	user.LastSeen = t
+	if channel != "" {
+		user.Badges[channel] = parseBadges(data.Tags["badges"])
+	}
+	d.byLogin[login] = id
+	var onRename = d.onRename
+	d.mutex.Unlock()
+
+	if rename != nil && onRename != nil {
+		onRename(*rename)
+	}
+}
+
+// evict removes e's user from the directory.
+// evict requires that the mutex lock is held for the UserDirectory.
+func (d *UserDirectory) evict(e *list.Element) {
+	var user = d.lru.Remove(e).(*UserRecord)
+	delete(d.byID, user.ID)
+	if d.byLogin[user.Login] == user.ID {
+		delete(d.byLogin, user.Login)
+	}
+}
+
+// copy returns a copy of the record that does not share its badges map.
+func (u *UserRecord) copy() UserRecord {
+	var user = *u
+	user.Badges = make(map[string][]Badge, len(u.Badges))
+	for channel, badges := range u.Badges {
+		user.Badges[channel] = badges
+	}
+	return user
+}
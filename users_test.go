@@ -0,0 +1,53 @@
+package tmi
+
+import "testing"
+
+func TestUserDirectory(t *testing.T) {
+	c := NewClient(NewClientConfig("", ""))
+	d := c.TrackUsers(2)
+
+	var renames []UserRename
+	d.OnRename(func(r UserRename) { renames = append(renames, r) })
+
+	c.handleIRCMessage("@badges=subscriber/12;color=#FF0000;display-name=Alice;user-id=1 :alice!alice@alice.tmi.twitch.tv PRIVMSG #a :hi")
+	c.handleIRCMessage("@badges=moderator/1;color=#FF0000;display-name=Alice;user-id=1 :alice!alice@alice.tmi.twitch.tv PRIVMSG #b :hi")
+	c.handleIRCMessage("@badges=;color=;display-name=Bob;login=bob;msg-id=sub;user-id=2 :tmi.twitch.tv USERNOTICE #a :sub")
+
+	alice, ok := d.ByID("1")
+	assertBoolsEqual(t, "ByID ok", ok, true)
+	assertStringsEqual(t, "Login", alice.Login, "alice")
+	assertStringsEqual(t, "DisplayName", alice.DisplayName, "Alice")
+	assertStringsEqual(t, "Color", alice.Color, "#FF0000")
+	assertStringsEqual(t, "LastChannel", alice.LastChannel, "#b")
+	assertBadgeSlicesEqual(t, "Badges #a", alice.Badges["#a"], []Badge{{Name: "subscriber", Value: 12, Version: "12"}})
+	assertBadgeSlicesEqual(t, "Badges #b", alice.Badges["#b"], []Badge{{Name: "moderator", Value: 1, Version: "1"}})
+
+	bob, ok := d.ByLogin("Bob")
+	assertBoolsEqual(t, "ByLogin ok", ok, true)
+	assertStringsEqual(t, "ID", bob.ID, "2")
+
+	// alice renames to alicia
+	c.handleIRCMessage("@display-name=Alicia;user-id=1 :alicia!alicia@alicia.tmi.twitch.tv PRIVMSG #a :new name")
+	assertIntsEqual(t, "len(renames)", len(renames), 1)
+	assertStringsEqual(t, "OldLogin", renames[0].OldLogin, "alice")
+	assertStringsEqual(t, "NewLogin", renames[0].NewLogin, "alicia")
+	assertStringsEqual(t, "NewDisplayName", renames[0].NewDisplayName, "Alicia")
+	if _, ok := d.ByLogin("alice"); ok {
+		t.Errorf("ByLogin: the old login should be gone")
+	}
+	alicia, _ := d.ByLogin("alicia")
+	assertStringsEqual(t, "Color kept", alicia.Color, "#FF0000")
+
+	// bob is the least recently active, and is evicted
+	c.handleIRCMessage("@display-name=Carol;user-id=3 :carol!carol@carol.tmi.twitch.tv PRIVMSG #a :hi")
+	assertIntsEqual(t, "Len", d.Len(), 2)
+	if _, ok := d.ByID("2"); ok {
+		t.Errorf("bob should have been evicted")
+	}
+	if _, ok := d.ByLogin("bob"); ok {
+		t.Errorf("bob's login should have been evicted")
+	}
+	if _, ok := d.ByID("1"); !ok {
+		t.Errorf("alicia should still be in the directory")
+	}
+}